@@ -0,0 +1,160 @@
+package broker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// subscriptionBuffer adalah jumlah pesan yang bisa menumpuk untuk satu subscriber
+// sebelum pesan berikutnya dibuang
+const subscriptionBuffer = 64
+
+var ErrClosed = errors.New("broker is closed")
+
+// Message adalah satu pesan pada sebuah topic
+type Message struct {
+	Topic       string      `json:"topic"`
+	Type        string      `json:"type"`
+	Data        interface{} `json:"data"`
+	PublishedAt time.Time   `json:"published_at"`
+}
+
+// Broker mengirim pesan ke semua subscriber sebuah topic. Implementasi in-process
+// dipakai secara default dan bisa diganti dengan broker eksternal seperti Redis atau NATS
+// selama memenuhi interface ini.
+type Broker interface {
+	Publish(topic, messageType string, data interface{}) error
+	Subscribe(topic string) (Subscription, error)
+	Close() error
+}
+
+// Subscription menerima pesan dari satu topic sampai Close dipanggil
+type Subscription interface {
+	Messages() <-chan Message
+	// Dropped menerima sinyal setelah ada pesan yang dibuang karena subscriber terlalu
+	// lambat. Subscriber sebaiknya memuat ulang state lengkap saat menerimanya.
+	Dropped() <-chan struct{}
+	Close()
+}
+
+type memoryBroker struct {
+	mu     sync.RWMutex
+	topics map[string]map[*memorySubscription]struct{}
+	closed bool
+}
+
+// NewMemoryBroker membuat broker yang hanya mengirim pesan di dalam proses ini.
+// Subscriber yang lambat tidak menahan publisher; pesan untuknya dibuang saat buffer penuh
+// dan subscriber diberi tahu lewat Dropped.
+func NewMemoryBroker() Broker {
+	return &memoryBroker{
+		topics: map[string]map[*memorySubscription]struct{}{},
+	}
+}
+
+func (b *memoryBroker) Publish(topic, messageType string, data interface{}) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+
+	message := Message{Topic: topic, Type: messageType, Data: data, PublishedAt: time.Now()}
+	for sub := range b.topics[topic] {
+		select {
+		case sub.messages <- message:
+		default:
+			select {
+			case sub.dropped <- struct{}{}:
+			default:
+			}
+		}
+	}
+	return nil
+}
+
+func (b *memoryBroker) Subscribe(topic string) (Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrClosed
+	}
+
+	sub := &memorySubscription{
+		broker:   b,
+		topic:    topic,
+		messages: make(chan Message, subscriptionBuffer),
+		dropped:  make(chan struct{}, 1),
+	}
+
+	if b.topics[topic] == nil {
+		b.topics[topic] = map[*memorySubscription]struct{}{}
+	}
+	b.topics[topic][sub] = struct{}{}
+
+	return sub, nil
+}
+
+// Close menutup semua subscription sehingga channel Messages milik subscriber ikut tertutup
+func (b *memoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+
+	for _, subs := range b.topics {
+		for sub := range subs {
+			close(sub.messages)
+		}
+	}
+	b.topics = map[string]map[*memorySubscription]struct{}{}
+	return nil
+}
+
+func (b *memoryBroker) unsubscribe(sub *memorySubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs, ok := b.topics[sub.topic]
+	if !ok {
+		return
+	}
+
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.messages)
+	if len(subs) == 0 {
+		delete(b.topics, sub.topic)
+	}
+}
+
+type memorySubscription struct {
+	broker   *memoryBroker
+	topic    string
+	messages chan Message
+	dropped  chan struct{}
+	once     sync.Once
+}
+
+func (s *memorySubscription) Messages() <-chan Message {
+	return s.messages
+}
+
+func (s *memorySubscription) Dropped() <-chan struct{} {
+	return s.dropped
+}
+
+func (s *memorySubscription) Close() {
+	s.once.Do(func() {
+		s.broker.unsubscribe(s)
+	})
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"event-ticketing/config"
+	"event-ticketing/entity"
+	"event-ticketing/oidc"
+	"event-ticketing/repository"
+	"event-ticketing/service"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// runCommand menjalankan perintah administrasi dari command line tanpa menyalakan server
+func runCommand(db *gorm.DB, args []string) error {
+	switch args[0] {
+	case "create-organization":
+		return createOrganization(db, args[1:])
+	case "create-admin":
+		return createAdmin(db, args[1:])
+	case "reconcile-inventory":
+		return reconcileInventory(db)
+	default:
+		return fmt.Errorf("unknown command: %s", args[0])
+	}
+}
+
+func createOrganization(db *gorm.DB, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: create-organization <name>")
+	}
+
+	organizationRepo := repository.NewOrganizationRepository(db)
+
+	existingOrganization, err := organizationRepo.FindByName(args[0])
+	if err == nil && existingOrganization != nil {
+		return errors.New("organization name already exists")
+	}
+
+	organization := &entity.Organization{Name: args[0]}
+	if err := organizationRepo.Create(organization); err != nil {
+		return err
+	}
+
+	log.Printf("Organization %s created with ID %s", organization.Name, organization.ID)
+	return nil
+}
+
+// createAdmin membuat admin pertama sebuah organisasi, atau mempromosikan user yang sudah ada.
+// Password dibaca dari ADMIN_PASSWORD atau stdin agar tidak tersimpan di riwayat shell.
+func createAdmin(db *gorm.DB, args []string) error {
+	if len(args) != 3 {
+		return errors.New("usage: create-admin <organization> <name> <email>")
+	}
+
+	organization, err := repository.NewOrganizationRepository(db).FindByName(args[0])
+	if err != nil {
+		return err
+	}
+
+	userRepo := repository.NewUserRepository(db).WithTenant(organization.ID.String())
+
+	existingUser, err := userRepo.FindByEmail(args[2])
+	if err == nil && existingUser != nil {
+		if err := userRepo.UpdateRole(existingUser.ID.String(), entity.AdminRole); err != nil {
+			return err
+		}
+
+		log.Printf("User %s promoted to admin", existingUser.Email)
+		return nil
+	}
+
+	password := os.Getenv("ADMIN_PASSWORD")
+	if password == "" {
+		fmt.Print("Password: ")
+		password, err = bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil {
+			return err
+		}
+		password = strings.TrimSpace(password)
+	}
+
+	if len(password) < 6 {
+		return errors.New("password must be at least 6 characters")
+	}
+
+	now := time.Now()
+	user := &entity.User{
+		Name:            args[1],
+		Email:           args[2],
+		Password:        password,
+		Role:            entity.AdminRole,
+		EmailVerifiedAt: &now,
+	}
+	if err := userRepo.Create(user); err != nil {
+		return err
+	}
+
+	log.Printf("Admin %s created with ID %s", user.Email, user.ID)
+	return nil
+}
+
+// mockOIDC menjalankan identity provider tiruan untuk mencoba login OIDC secara lokal,
+// misalnya dengan OIDC_PROVIDERS=mock dan OIDC_MOCK_ISSUER=http://localhost:9000
+func mockOIDC(args []string) error {
+	addr := "localhost:9000"
+	if len(args) > 0 {
+		addr = args[0]
+	}
+
+	server, err := oidc.NewMockServer("http://" + addr)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Mock OIDC provider listening on http://%s", addr)
+	return http.ListenAndServe(addr, server.Handler())
+}
+
+// reconcileInventory memperbaiki counter tickets_sold yang tidak sesuai dengan data tiket
+func reconcileInventory(db *gorm.DB) error {
+	drifts, err := service.NewInventoryReconciler(repository.NewEventRepository(db), config.Config{}).Reconcile()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("%d event(s) reconciled", len(drifts))
+	return nil
+}
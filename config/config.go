@@ -3,9 +3,20 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// OIDCProviderConfig berisi pengaturan satu identity provider OpenID Connect
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
 type Config struct {
 	AppName     string
 	AppPort     string
@@ -18,11 +29,76 @@ type Config struct {
 	DBPassword string
 	DBName     string
 
-	JWTSecret    string
-	JWTExpiresIn time.Duration
+	JWTSecret              string
+	JWTExpiresIn           time.Duration
+	RefreshTokenExpiresIn  time.Duration
+	JWTAlgorithm           string
+	JWTKeySecret           string
+	JWTKeyRotationInterval time.Duration
+	// JWTHS256AcceptUntil membatasi sampai kapan token HS256 lama diterima setelah pindah ke
+	// RS256/EdDSA. Jika kosong, token diterima sampai token terakhir yang terbit sebelum key
+	// asimetris pertama dibuat kedaluwarsa. Isi dengan waktu lampau untuk langsung menolaknya.
+	JWTHS256AcceptUntil time.Time
+
+	DefaultOrganization string
+
+	FrontendURL              string
+	PasswordResetExpiresIn   time.Duration
+	EmailVerifyExpiresIn     time.Duration
+	RequireEmailVerification bool
+
+	RequireAdminTwoFactor  bool
+	TwoFactorEncryptionKey string
+
+	LoginMaxAttempts      int
+	LoginMaxAttemptsPerIP int
+	LoginAttemptWindow    time.Duration
+	LoginLockoutDuration  time.Duration
+
+	OIDCProviders []OIDCProviderConfig
+
+	QueueAdmitInterval   time.Duration
+	QueueAdmissionWindow time.Duration
+
+	InventoryReconcileInterval time.Duration
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+
+	// MailCaptureDir menyimpan email sebagai file .eml alih-alih mengirimnya
+	MailCaptureDir string
+
+	ReportSchedulerInterval time.Duration
+
+	// NotificationTimezone dipakai untuk menampilkan tanggal event di email
+	NotificationTimezone         string
+	NotificationMaxAttempts      int
+	NotificationRetryDelay       time.Duration
+	NotificationDispatchInterval time.Duration
+
+	// ReminderOffsets adalah jarak sebelum event dimulai saat pengingat dikirim
+	ReminderOffsets           []time.Duration
+	ReminderSchedulerInterval time.Duration
+
+	// WebhookSecretKey mengenkripsi secret webhook di database
+	WebhookSecretKey        string
+	WebhookDispatchInterval time.Duration
+	WebhookTimeout          time.Duration
+	WebhookMaxAttempts      int
+	WebhookRetryDelay       time.Duration
+
+	OutboxRelayInterval time.Duration
+	OutboxRetryDelay    time.Duration
+	// OutboxRetention adalah lama pesan yang sudah terkirim disimpan sebelum dihapus
+	OutboxRetention time.Duration
 }
 
 func LoadConfig() Config {
+	jwtSecret := getEnv("JWT_SECRET", "your-secret-key")
+
 	config := Config{
 		AppName:     getEnv("APP_NAME", "Ticketing System API"),
 		AppPort:     getEnv("APP_PORT", "8080"),
@@ -35,13 +111,91 @@ func LoadConfig() Config {
 		DBPassword: getEnv("DB_PASSWORD", "password"),
 		DBName:     getEnv("DB_NAME", "ticketing_system"),
 
-		JWTSecret:    getEnv("JWT_SECRET", "your-secret-key"),
-		JWTExpiresIn: time.Duration(getEnvAsInt("JWT_EXPIRES_IN", 24)) * time.Hour,
+		JWTSecret:              jwtSecret,
+		JWTExpiresIn:           time.Duration(getEnvAsInt("JWT_EXPIRES_IN_MINUTES", 15)) * time.Minute,
+		RefreshTokenExpiresIn:  time.Duration(getEnvAsInt("REFRESH_TOKEN_EXPIRES_IN", 720)) * time.Hour,
+		JWTAlgorithm:           getEnv("JWT_ALGORITHM", "RS256"),
+		JWTKeySecret:           getEnv("JWT_KEY_SECRET", jwtSecret),
+		JWTKeyRotationInterval: time.Duration(getEnvAsInt("JWT_KEY_ROTATION_INTERVAL", 720)) * time.Hour,
+		JWTHS256AcceptUntil:    getEnvAsTime("JWT_HS256_ACCEPT_UNTIL", time.Time{}),
+
+		DefaultOrganization: getEnv("DEFAULT_ORGANIZATION", "Default"),
+
+		FrontendURL:              getEnv("FRONTEND_URL", "http://localhost:3000"),
+		PasswordResetExpiresIn:   time.Duration(getEnvAsInt("PASSWORD_RESET_EXPIRES_IN", 60)) * time.Minute,
+		EmailVerifyExpiresIn:     time.Duration(getEnvAsInt("EMAIL_VERIFY_EXPIRES_IN", 24)) * time.Hour,
+		RequireEmailVerification: getEnvAsBool("REQUIRE_EMAIL_VERIFICATION", false),
+
+		RequireAdminTwoFactor:  getEnvAsBool("REQUIRE_ADMIN_2FA", false),
+		TwoFactorEncryptionKey: getEnv("TWO_FACTOR_ENCRYPTION_KEY", jwtSecret),
+
+		LoginMaxAttempts:      getEnvAsInt("LOGIN_MAX_ATTEMPTS", 5),
+		LoginMaxAttemptsPerIP: getEnvAsInt("LOGIN_MAX_ATTEMPTS_PER_IP", 20),
+		LoginAttemptWindow:    time.Duration(getEnvAsInt("LOGIN_ATTEMPT_WINDOW", 15)) * time.Minute,
+		LoginLockoutDuration:  time.Duration(getEnvAsInt("LOGIN_LOCKOUT_DURATION", 15)) * time.Minute,
+
+		QueueAdmitInterval:   time.Duration(getEnvAsInt("QUEUE_ADMIT_INTERVAL", 5)) * time.Second,
+		QueueAdmissionWindow: time.Duration(getEnvAsInt("QUEUE_ADMISSION_WINDOW", 10)) * time.Minute,
+
+		InventoryReconcileInterval: time.Duration(getEnvAsInt("INVENTORY_RECONCILE_INTERVAL", 15)) * time.Minute,
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		MailFrom:     getEnv("MAIL_FROM", "no-reply@event-ticketing.local"),
+
+		MailCaptureDir: getEnv("MAIL_CAPTURE_DIR", ""),
+
+		ReportSchedulerInterval: time.Duration(getEnvAsInt("REPORT_SCHEDULER_INTERVAL", 60)) * time.Second,
+
+		NotificationTimezone:         getEnv("NOTIFICATION_TIMEZONE", "Asia/Jakarta"),
+		NotificationMaxAttempts:      getEnvAsInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+		NotificationRetryDelay:       time.Duration(getEnvAsInt("NOTIFICATION_RETRY_DELAY", 30)) * time.Second,
+		NotificationDispatchInterval: time.Duration(getEnvAsInt("NOTIFICATION_DISPATCH_INTERVAL", 2)) * time.Second,
+
+		ReminderOffsets:           getEnvAsDurations("REMINDER_OFFSETS", []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, 2 * time.Hour}),
+		ReminderSchedulerInterval: time.Duration(getEnvAsInt("REMINDER_SCHEDULER_INTERVAL", 60)) * time.Second,
+
+		WebhookSecretKey:        getEnv("WEBHOOK_SECRET_KEY", jwtSecret),
+		WebhookDispatchInterval: time.Duration(getEnvAsInt("WEBHOOK_DISPATCH_INTERVAL", 5)) * time.Second,
+		WebhookTimeout:          time.Duration(getEnvAsInt("WEBHOOK_TIMEOUT", 10)) * time.Second,
+		WebhookMaxAttempts:      getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookRetryDelay:       time.Duration(getEnvAsInt("WEBHOOK_RETRY_DELAY", 30)) * time.Second,
+
+		OutboxRelayInterval: time.Duration(getEnvAsInt("OUTBOX_RELAY_INTERVAL", 1)) * time.Second,
+		OutboxRetryDelay:    time.Duration(getEnvAsInt("OUTBOX_RETRY_DELAY", 5)) * time.Second,
+		OutboxRetention:     time.Duration(getEnvAsInt("OUTBOX_RETENTION", 168)) * time.Hour,
+
+		OIDCProviders: loadOIDCProviders(),
 	}
 
 	return config
 }
 
+// loadOIDCProviders membaca daftar provider dari OIDC_PROVIDERS, misalnya "google,mock",
+// lalu pengaturan tiap provider dari OIDC_<NAMA>_ISSUER, OIDC_<NAMA>_CLIENT_ID, dan seterusnya
+func loadOIDCProviders() []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(getEnv("OIDC_PROVIDERS", ""), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			Issuer:       getEnv(prefix+"ISSUER", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", ""),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "openid email profile")),
+		})
+	}
+	return providers
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -57,3 +211,59 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvAsTime(key string, defaultValue time.Time) time.Time {
+	if value, exists := os.LookupEnv(key); exists {
+		if timeValue, err := time.Parse(time.RFC3339, value); err == nil {
+			return timeValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsDurations membaca daftar durasi dipisah koma, misalnya "7d,24h,2h". Selain satuan
+// time.ParseDuration, "d" berarti hari. Nilai yang tidak valid membuat default dipakai.
+func getEnvAsDurations(key string, defaultValue []time.Duration) []time.Duration {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	var durations []time.Duration
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		var duration time.Duration
+		if strings.HasSuffix(part, "d") {
+			days, err := strconv.Atoi(strings.TrimSuffix(part, "d"))
+			if err != nil {
+				return defaultValue
+			}
+			duration = time.Duration(days) * 24 * time.Hour
+		} else {
+			parsed, err := time.ParseDuration(part)
+			if err != nil {
+				return defaultValue
+			}
+			duration = parsed
+		}
+
+		if duration < time.Minute {
+			return defaultValue
+		}
+		durations = append(durations, duration)
+	}
+	return durations
+}
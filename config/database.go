@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 
+	"github.com/gofrs/uuid/v5"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -32,11 +33,87 @@ func SetupDatabase(config Config) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	if err := db.AutoMigrate(&entity.User{}, &entity.Event{}, &entity.Ticket{}); err != nil {
+	// Counter tickets_sold diisi dari data tiket saat kolomnya pertama kali dibuat
+	backfillTicketsSold := !db.Migrator().HasColumn(&entity.Event{}, "TicketsSold")
+
+	if err := Migrate(db); err != nil {
 		log.Printf("Failed to migrate database: %v", err)
 		return nil, err
 	}
 
+	if err := seedDefaultOrganization(db, config.DefaultOrganization); err != nil {
+		log.Printf("Failed to seed default organization: %v", err)
+		return nil, err
+	}
+
+	if err := anonymizeDeletedUsers(db); err != nil {
+		log.Printf("Failed to anonymize deleted users: %v", err)
+		return nil, err
+	}
+
+	if backfillTicketsSold {
+		if err := fillTicketsSold(db); err != nil {
+			log.Printf("Failed to backfill tickets sold: %v", err)
+			return nil, err
+		}
+	}
+
 	log.Println("Database connected successfully")
 	return db, nil
 }
+
+// Migrate membuat atau memperbarui semua tabel. Dipakai juga oleh test yang berjalan di database MySQL.
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.Organization{}, &entity.User{}, &entity.Event{}, &entity.Ticket{},
+		&entity.RefreshToken{}, &entity.RevokedToken{}, &entity.SigningKey{}, &entity.UserToken{}, &entity.RecoveryCode{},
+		&entity.LoginThrottle{}, &entity.AuditLog{}, &entity.APIKey{},
+		&entity.OIDCState{}, &entity.UserIdentity{}, &entity.QueueEntry{}, &entity.ReportSubscription{}, &entity.Notification{}, &entity.TicketReminder{},
+		&entity.WebhookSubscription{}, &entity.WebhookDelivery{}, &entity.OutboxMessage{})
+}
+
+// seedDefaultOrganization memastikan organisasi default ada dan memindahkan
+// data lama yang belum memiliki tenant ke organisasi tersebut
+func seedDefaultOrganization(db *gorm.DB, name string) error {
+	var organization entity.Organization
+	if err := db.Where(entity.Organization{Name: name}).FirstOrCreate(&organization).Error; err != nil {
+		return err
+	}
+
+	for _, model := range []interface{}{&entity.User{}, &entity.Event{}, &entity.Ticket{}} {
+		err := db.Unscoped().Model(model).
+			Where("organization_id IS NULL OR organization_id = ?", uuid.Nil).
+			Update("organization_id", organization.ID).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// anonymizeDeletedUsers membersihkan PII dari user yang dihapus sebelum
+// penghapusan akun ikut menganonimkan data
+func anonymizeDeletedUsers(db *gorm.DB) error {
+	var ids []string
+	err := db.Unscoped().Model(&entity.User{}).
+		Where("deleted_at IS NOT NULL AND email NOT LIKE ?", "%"+entity.AnonymizedEmailDomain).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := db.Unscoped().Model(&entity.User{}).Where("id = ?", id).Updates(entity.AnonymizedUserColumns(id)).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func fillTicketsSold(db *gorm.DB) error {
+	return db.Exec(`UPDATE events SET tickets_sold = (
+		SELECT COUNT(*) FROM tickets
+		WHERE tickets.event_id = events.id AND tickets.status = ? AND tickets.deleted_at IS NULL
+	)`, entity.PurchasedTicket).Error
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"event-ticketing/dto"
+	"event-ticketing/service"
+	"event-ticketing/utils"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountController interface {
+	ExportData(c *gin.Context)
+	DeleteAccount(c *gin.Context)
+	GetNotifications(c *gin.Context)
+}
+
+type accountController struct {
+	accountService service.AccountService
+}
+
+func NewAccountController(accountService service.AccountService) AccountController {
+	return &accountController{
+		accountService: accountService,
+	}
+}
+
+// ExportData godoc
+// @Summary Export personal data
+// @Description Download the profile, tickets (orders) and sessions of the current user as JSON or a ZIP archive
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Produce application/zip
+// @Security ApiKeyAuth
+// @Param format query string false "Export format: json or zip (default: json)"
+// @Success 200 {object} service.AccountExport
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/me/export [get]
+func (ctrl *accountController) ExportData(c *gin.Context) {
+	var log = utils.Log
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		utils.BadRequestResponse(c, "Invalid format", "format must be json or zip")
+		return
+	}
+
+	export, err := ctrl.accountService.WithTenant(c.GetString("organizationID")).ExportData(c.GetString("userID"))
+	if err != nil {
+		log.Errorf("Failed to export user data: %v", err)
+		utils.InternalServerErrorResponse(c, "Failed to export user data", err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("account-export-%s.%s", export.ExportedAt.Format("20060102150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "json" {
+		c.JSON(http.StatusOK, export)
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Status(http.StatusOK)
+	if err := export.WriteZip(c.Writer); err != nil {
+		log.Errorf("Failed to write export archive: %v", err)
+	}
+}
+
+// DeleteAccount godoc
+// @Summary Delete account
+// @Description Delete the current account. Personal data is anonymized while ticket records are kept for accounting
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dto.DeleteAccountRequestDto true "Password confirmation"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /auth/me [delete]
+func (ctrl *accountController) DeleteAccount(c *gin.Context) {
+	var log = utils.Log
+	var request dto.DeleteAccountRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := ctrl.accountService.WithTenant(c.GetString("organizationID")).DeleteAccount(c.GetString("userID"), request.Password); err != nil {
+		log.Errorf("Delete account failed: %v", err)
+		utils.BadRequestResponse(c, "Failed to delete account", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account deleted successfully", nil)
+}
+
+// GetNotifications godoc
+// @Summary List my notifications
+// @Description List emails sent to the current user such as ticket confirmations and event changes, with their delivery status
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query string false "Page number (default: 1)"
+// @Param limit query string false "Results per page (default: 10)"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/me/notifications [get]
+func (ctrl *accountController) GetNotifications(c *gin.Context) {
+	var log = utils.Log
+
+	params := utils.NewPaginationParams(c.DefaultQuery("page", "1"), c.DefaultQuery("limit", "10"))
+
+	notifications, totalItems, err := ctrl.accountService.WithTenant(c.GetString("organizationID")).GetNotifications(c.GetString("userID"), params)
+	if err != nil {
+		log.Errorf("Failed to retrieve notifications: %v", err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve notifications", err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Notifications retrieved successfully", notifications, totalItems, params.Page, params.Limit)
+}
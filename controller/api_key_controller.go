@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"event-ticketing/dto"
+	"event-ticketing/service"
+	"event-ticketing/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIKeyController interface {
+	CreateAPIKey(c *gin.Context)
+	GetAllAPIKeys(c *gin.Context)
+	RevokeAPIKey(c *gin.Context)
+}
+
+type apiKeyController struct {
+	apiKeyService service.APIKeyService
+}
+
+func NewAPIKeyController(apiKeyService service.APIKeyService) APIKeyController {
+	return &apiKeyController{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary Issue an API key
+// @Description Issue a scoped API key for a partner. The key is only returned once (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dto.CreateAPIKeyRequestDto true "API key data"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/api-keys [post]
+func (ctrl *apiKeyController) CreateAPIKey(c *gin.Context) {
+	var log = utils.Log
+	var request dto.CreateAPIKeyRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	key, err := ctrl.apiKeyService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).
+		CreateAPIKey(request.UserID, request.Name, request.Scopes, request.ExpiresAt)
+	if err != nil {
+		log.Errorf("Failed to create API key: %v", err)
+		utils.BadRequestResponse(c, "Failed to create API key", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "API key created successfully", key)
+}
+
+// GetAllAPIKeys godoc
+// @Summary List API keys
+// @Description List API keys of the organization with their scopes and last use (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query string false "Page number (default: 1)"
+// @Param limit query string false "Results per page (default: 10)"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/api-keys [get]
+func (ctrl *apiKeyController) GetAllAPIKeys(c *gin.Context) {
+	var log = utils.Log
+
+	params := utils.NewPaginationParams(c.DefaultQuery("page", "1"), c.DefaultQuery("limit", "10"))
+
+	keys, totalItems, err := ctrl.apiKeyService.WithTenant(c.GetString("organizationID")).GetAllAPIKeys(params)
+	if err != nil {
+		log.Errorf("Failed to retrieve API keys: %v", err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve API keys", err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "API keys retrieved successfully", keys, totalItems, params.Page, params.Limit)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revoke an API key so it can no longer be used (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/api-keys/{id} [delete]
+func (ctrl *apiKeyController) RevokeAPIKey(c *gin.Context) {
+	var log = utils.Log
+
+	if err := ctrl.apiKeyService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).RevokeAPIKey(c.Param("id")); err != nil {
+		log.Errorf("Failed to revoke API key: %v", err)
+		utils.BadRequestResponse(c, "Failed to revoke API key", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API key revoked successfully", nil)
+}
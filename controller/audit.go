@@ -0,0 +1,17 @@
+package controller
+
+import (
+	"event-ticketing/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auditActor mengambil identitas pelaku perubahan dari request untuk audit log
+func auditActor(c *gin.Context) service.Actor {
+	return service.Actor{
+		UserID:    c.GetString("userID"),
+		APIKeyID:  c.GetString("apiKeyID"),
+		IP:        c.ClientIP(),
+		RequestID: c.GetString("requestID"),
+	}
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"event-ticketing/repository"
+	"event-ticketing/service"
+	"event-ticketing/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditLogController interface {
+	GetAuditLogs(c *gin.Context)
+}
+
+type auditLogController struct {
+	auditService service.AuditService
+}
+
+func NewAuditLogController(auditService service.AuditService) AuditLogController {
+	return &auditLogController{
+		auditService: auditService,
+	}
+}
+
+// GetAuditLogs godoc
+// @Summary List audit logs
+// @Description Search the audit trail of the organization, newest first (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query string false "Page number (default: 1)"
+// @Param limit query string false "Results per page (default: 10)"
+// @Param actor_id query string false "User who made the change"
+// @Param action query string false "Action, e.g. event.updated or ticket.cancelled"
+// @Param entity_type query string false "Entity type, e.g. event, ticket or user"
+// @Param entity_id query string false "Entity ID"
+// @Param request_id query string false "Request ID"
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date (YYYY-MM-DD)"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/audit-logs [get]
+func (ctrl *auditLogController) GetAuditLogs(c *gin.Context) {
+	var log = utils.Log
+
+	params := utils.NewPaginationParams(c.DefaultQuery("page", "1"), c.DefaultQuery("limit", "10"))
+	filter := repository.AuditLogFilter{
+		ActorID:    c.Query("actor_id"),
+		Action:     c.Query("action"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+		RequestID:  c.Query("request_id"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+	}
+
+	logs, totalItems, err := ctrl.auditService.WithTenant(c.GetString("organizationID")).GetAuditLogs(params, filter)
+	if err != nil {
+		log.Errorf("Failed to retrieve audit logs: %v", err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve audit logs", err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Audit logs retrieved successfully", logs, totalItems, params.Page, params.Limit)
+}
@@ -1,8 +1,8 @@
 package controller
 
 import (
+	"errors"
 	"event-ticketing/dto"
-	"event-ticketing/entity"
 	"event-ticketing/service"
 	"event-ticketing/utils"
 	"net/http"
@@ -13,7 +13,20 @@ import (
 type AuthController interface {
 	Register(c *gin.Context)
 	Login(c *gin.Context)
+	VerifyTwoFactorLogin(c *gin.Context)
+	RefreshToken(c *gin.Context)
+	Logout(c *gin.Context)
+	LogoutAll(c *gin.Context)
+	ForgotPassword(c *gin.Context)
+	ResetPassword(c *gin.Context)
+	VerifyEmail(c *gin.Context)
+	ResendVerification(c *gin.Context)
+	SetupTwoFactor(c *gin.Context)
+	EnableTwoFactor(c *gin.Context)
+	DisableTwoFactor(c *gin.Context)
 	GetProfile(c *gin.Context)
+	UpdateProfile(c *gin.Context)
+	ChangePassword(c *gin.Context)
 }
 
 type authController struct {
@@ -32,7 +45,8 @@ func NewAuthController(authService service.AuthService) AuthController {
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param user body entity.User true "User registration info"
+// @Param X-Organization-ID header string true "Organization ID"
+// @Param user body dto.RegisterRequestDto true "User registration info"
 // @Success 201 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 409 {object} utils.Response
@@ -40,21 +54,22 @@ func NewAuthController(authService service.AuthService) AuthController {
 // @Router /auth/register [post]
 func (ctrl *authController) Register(c *gin.Context) {
 	var log = utils.Log
-	var user entity.User
+	var request dto.RegisterRequestDto
 
-	if err := c.ShouldBindJSON(&user); err != nil {
+	if err := c.ShouldBindJSON(&request); err != nil {
 		log.Errorf("Failed to bind JSON: %v", err)
 		utils.BadRequestResponse(c, "Invalid request body", err.Error())
 		return
 	}
 
-	if err := utils.ValidateStruct(user); err != nil {
+	if err := utils.ValidateStruct(request); err != nil {
 		log.Errorf("Validation error: %v", err)
 		utils.BadRequestResponse(c, "Validation error", err.Error())
 		return
 	}
 
-	if err := ctrl.authService.Register(&user); err != nil {
+	user := request.ToEntity()
+	if err := ctrl.authService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).Register(user); err != nil {
 		log.Errorf("Registration failed: %v", err)
 		utils.ConflictResponse(c, "Registration failed", err.Error())
 		return
@@ -71,10 +86,12 @@ func (ctrl *authController) Register(c *gin.Context) {
 // @Tags auth
 // @Accept json
 // @Produce json
+// @Param X-Organization-ID header string true "Organization ID"
 // @Param user body dto.UserRequestDto true "User registration info"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 429 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /auth/login [post]
 func (ctrl *authController) Login(c *gin.Context) {
@@ -87,14 +104,331 @@ func (ctrl *authController) Login(c *gin.Context) {
 		return
 	}
 
-	token, err := ctrl.authService.Login(request.Email, request.Password)
+	result, err := ctrl.authService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).Login(request.Email, request.Password, c.ClientIP())
+	if errors.Is(err, service.ErrTooManyAttempts) {
+		log.Warnf("Login throttled for %s from %s", request.Email, c.ClientIP())
+		utils.TooManyRequestsResponse(c, "Too many login attempts, please try again later")
+		return
+	}
 	if err != nil {
 		log.Errorf("Login failed: %v", err)
 		utils.UnauthorizedResponse(c, "Invalid credentials")
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Login successful", gin.H{"token": token})
+	if result.TwoFactorRequired {
+		utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication required", result)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", result)
+}
+
+// VerifyTwoFactorLogin godoc
+// @Summary Complete two-factor login
+// @Description Exchange a login challenge and a TOTP or recovery code for tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param X-Organization-ID header string true "Organization ID"
+// @Param request body dto.TwoFactorLoginRequestDto true "Challenge token and code"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /auth/login/2fa [post]
+func (ctrl *authController) VerifyTwoFactorLogin(c *gin.Context) {
+	var log = utils.Log
+	var request dto.TwoFactorLoginRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	tokens, err := ctrl.authService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).VerifyTwoFactorLogin(request.ChallengeToken, request.Code)
+	if err != nil {
+		log.Errorf("Two-factor login failed: %v", err)
+		utils.UnauthorizedResponse(c, "Invalid two-factor code")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", tokens)
+}
+
+// RefreshToken godoc
+// @Summary Refresh access token
+// @Description Exchange a refresh token for a new access token and a rotated refresh token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param X-Organization-ID header string true "Organization ID"
+// @Param request body dto.RefreshTokenRequestDto true "Refresh token"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /auth/refresh [post]
+func (ctrl *authController) RefreshToken(c *gin.Context) {
+	var log = utils.Log
+	var request dto.RefreshTokenRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	tokens, err := ctrl.authService.WithTenant(c.GetString("organizationID")).RefreshToken(request.RefreshToken)
+	if err != nil {
+		log.Errorf("Refresh token failed: %v", err)
+		utils.UnauthorizedResponse(c, "Invalid refresh token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Token refreshed successfully", tokens)
+}
+
+// Logout godoc
+// @Summary Logout current session
+// @Description Revoke the current access token and its refresh token
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/logout [post]
+func (ctrl *authController) Logout(c *gin.Context) {
+	var log = utils.Log
+
+	if err := ctrl.authService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).Logout(c.GetString("tokenID")); err != nil {
+		log.Errorf("Logout failed: %v", err)
+		utils.InternalServerErrorResponse(c, "Logout failed", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logout successful", nil)
+}
+
+// LogoutAll godoc
+// @Summary Logout all sessions
+// @Description Revoke every access and refresh token of the current user
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/logout-all [post]
+func (ctrl *authController) LogoutAll(c *gin.Context) {
+	var log = utils.Log
+
+	if err := ctrl.authService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).LogoutAll(c.GetString("userID")); err != nil {
+		log.Errorf("Logout all failed: %v", err)
+		utils.InternalServerErrorResponse(c, "Logout failed", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "All sessions logged out successfully", nil)
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Send a password reset link if the email is registered
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param X-Organization-ID header string true "Organization ID"
+// @Param request body dto.ForgotPasswordRequestDto true "Account email"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /auth/forgot-password [post]
+func (ctrl *authController) ForgotPassword(c *gin.Context) {
+	var log = utils.Log
+	var request dto.ForgotPasswordRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := ctrl.authService.WithTenant(c.GetString("organizationID")).ForgotPassword(request.Email); err != nil {
+		log.Errorf("Forgot password failed: %v", err)
+		utils.InternalServerErrorResponse(c, "Failed to process request", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "If the email is registered, a reset link has been sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password using a password reset token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param X-Organization-ID header string true "Organization ID"
+// @Param request body dto.ResetPasswordRequestDto true "Reset token and new password"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /auth/reset-password [post]
+func (ctrl *authController) ResetPassword(c *gin.Context) {
+	var log = utils.Log
+	var request dto.ResetPasswordRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := ctrl.authService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).ResetPassword(request.Token, request.Password); err != nil {
+		log.Errorf("Reset password failed: %v", err)
+		utils.BadRequestResponse(c, "Failed to reset password", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password reset successfully", nil)
+}
+
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Confirm the user's email address using a verification token
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param X-Organization-ID header string true "Organization ID"
+// @Param request body dto.VerifyEmailRequestDto true "Verification token"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /auth/verify-email [post]
+func (ctrl *authController) VerifyEmail(c *gin.Context) {
+	var log = utils.Log
+	var request dto.VerifyEmailRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := ctrl.authService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).VerifyEmail(request.Token); err != nil {
+		log.Errorf("Email verification failed: %v", err)
+		utils.BadRequestResponse(c, "Failed to verify email", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email verified successfully", nil)
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Send a new email verification link to the current user
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /auth/verify-email/resend [post]
+func (ctrl *authController) ResendVerification(c *gin.Context) {
+	var log = utils.Log
+
+	if err := ctrl.authService.WithTenant(c.GetString("organizationID")).SendVerificationEmail(c.GetString("userID")); err != nil {
+		log.Errorf("Resend verification failed: %v", err)
+		utils.BadRequestResponse(c, "Failed to send verification email", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Verification email sent", nil)
+}
+
+// SetupTwoFactor godoc
+// @Summary Start two-factor enrollment
+// @Description Generate a TOTP secret and otpauth URI for the current user
+// @Tags auth
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /auth/2fa/setup [post]
+func (ctrl *authController) SetupTwoFactor(c *gin.Context) {
+	var log = utils.Log
+
+	setup, err := ctrl.authService.WithTenant(c.GetString("organizationID")).SetupTwoFactor(c.GetString("userID"))
+	if err != nil {
+		log.Errorf("Two-factor setup failed: %v", err)
+		utils.BadRequestResponse(c, "Failed to set up two-factor authentication", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Scan the QR code with your authenticator app", setup)
+}
+
+// EnableTwoFactor godoc
+// @Summary Enable two-factor authentication
+// @Description Confirm enrollment with a TOTP code and receive recovery codes
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dto.TwoFactorCodeRequestDto true "TOTP code"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /auth/2fa/enable [post]
+func (ctrl *authController) EnableTwoFactor(c *gin.Context) {
+	var log = utils.Log
+	var request dto.TwoFactorCodeRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	codes, err := ctrl.authService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).EnableTwoFactor(c.GetString("userID"), request.Code)
+	if err != nil {
+		log.Errorf("Enable two-factor failed: %v", err)
+		utils.BadRequestResponse(c, "Failed to enable two-factor authentication", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication enabled, please login again", gin.H{"recovery_codes": codes})
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable two-factor authentication
+// @Description Turn off two-factor authentication using a TOTP or recovery code
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dto.TwoFactorCodeRequestDto true "TOTP or recovery code"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /auth/2fa/disable [post]
+func (ctrl *authController) DisableTwoFactor(c *gin.Context) {
+	var log = utils.Log
+	var request dto.TwoFactorCodeRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	if err := ctrl.authService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).DisableTwoFactor(c.GetString("userID"), request.Code); err != nil {
+		log.Errorf("Disable two-factor failed: %v", err)
+		utils.BadRequestResponse(c, "Failed to disable two-factor authentication", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication disabled", nil)
 }
 
 // @Summary Get user profile
@@ -117,7 +451,7 @@ func (ctrl *authController) GetProfile(c *gin.Context) {
 		return
 	}
 
-	user, err := ctrl.authService.GetUserByID(userID.(string))
+	user, err := ctrl.authService.WithTenant(c.GetString("organizationID")).GetUserByID(userID.(string))
 	if err != nil {
 		log.Errorf("Failed to get user profile: %v", err)
 		utils.InternalServerErrorResponse(c, "Failed to get user profile", err.Error())
@@ -128,3 +462,74 @@ func (ctrl *authController) GetProfile(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "Profile retrieved successfully", user)
 }
+
+// UpdateProfile godoc
+// @Summary Update user profile
+// @Description Update the name, email language and event reminder opt-out of the current user. A new email address only takes effect after it is verified
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dto.UpdateProfileRequestDto true "Profile data"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 409 {object} utils.Response
+// @Router /auth/profile [put]
+func (ctrl *authController) UpdateProfile(c *gin.Context) {
+	var log = utils.Log
+	var request dto.UpdateProfileRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	user, err := ctrl.authService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).UpdateProfile(c.GetString("userID"), request.Name, request.Email, request.Language, request.EventRemindersOptOut)
+	if err != nil {
+		log.Errorf("Update profile failed: %v", err)
+		if err.Error() == "email already in use" {
+			utils.ConflictResponse(c, "Failed to update profile", err.Error())
+			return
+		}
+		utils.BadRequestResponse(c, "Failed to update profile", err.Error())
+		return
+	}
+
+	user.Password = ""
+
+	utils.SuccessResponse(c, http.StatusOK, "Profile updated successfully", user)
+}
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change the password of the current user. Other sessions are signed out
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dto.ChangePasswordRequestDto true "Current and new password"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /auth/password [put]
+func (ctrl *authController) ChangePassword(c *gin.Context) {
+	var log = utils.Log
+	var request dto.ChangePasswordRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	err := ctrl.authService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).ChangePassword(c.GetString("userID"), c.GetString("tokenID"), request.CurrentPassword, request.NewPassword)
+	if err != nil {
+		log.Errorf("Change password failed: %v", err)
+		utils.BadRequestResponse(c, "Failed to change password", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password changed successfully", nil)
+}
@@ -34,6 +34,7 @@ func NewEventController(eventService service.EventService) EventController {
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security PartnerKeyAuth
 // @Param event body dto.CreateEventReqDto true "Event creation info"
 // @Success 201 {object} utils.Response
 // @Failure 400 {object} utils.Response
@@ -75,7 +76,7 @@ func (ctrl *eventController) CreateEvent(c *gin.Context) {
 
 	event := eventRequest.ToEntity()
 
-	if err := ctrl.eventService.CreateEvent(event); err != nil {
+	if err := ctrl.eventService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).CreateEvent(event); err != nil {
 		log.Errorf("Event creation failed: %v", err)
 		utils.ConflictResponse(c, "Event creation failed", err.Error())
 		return
@@ -90,6 +91,7 @@ func (ctrl *eventController) CreateEvent(c *gin.Context) {
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param X-Organization-ID header string true "Organization ID"
 // @Param id path string true "Event ID"
 // @Success 200 {object} utils.Response
 // @Failure 404 {object} utils.Response
@@ -99,7 +101,7 @@ func (ctrl *eventController) GetEventByID(c *gin.Context) {
 	var log = utils.Log
 
 	id := c.Param("id")
-	event, err := ctrl.eventService.GetEventByID(id)
+	event, err := ctrl.eventService.WithTenant(c.GetString("organizationID")).GetEventByID(id)
 	if err != nil {
 		log.Errorf("Event not found: %v", err)
 		utils.NotFoundResponse(c, "Event not found")
@@ -115,6 +117,7 @@ func (ctrl *eventController) GetEventByID(c *gin.Context) {
 // @Tags events
 // @Accept json
 // @Produce json
+// @Param X-Organization-ID header string true "Organization ID"
 // @Param page query string false "Page number (default: 1)"
 // @Param limit query string false "Results per page (default: 10)"
 // @Param keyword query string false "Search keyword"
@@ -134,7 +137,7 @@ func (ctrl *eventController) GetAllEvents(c *gin.Context) {
 	startDate := c.Query("start_date")
 	endDate := c.Query("end_date")
 
-	events, totalItems, err := ctrl.eventService.GetAllEvents(params, keyword, status, startDate, endDate)
+	events, totalItems, err := ctrl.eventService.WithTenant(c.GetString("organizationID")).GetAllEvents(params, keyword, status, startDate, endDate)
 	if err != nil {
 		log.Errorf("Failed to retrieve events: %v", err)
 		utils.InternalServerErrorResponse(c, "Failed to retrieve events", err.Error())
@@ -151,6 +154,7 @@ func (ctrl *eventController) GetAllEvents(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security PartnerKeyAuth
 // @Param id path string true "Event ID"
 // @Param event body dto.UpdateEventReqDto true "Updated event info"
 // @Success 200 {object} utils.Response
@@ -187,7 +191,7 @@ func (ctrl *eventController) UpdateEvent(c *gin.Context) {
 	updatedEvent.ID = IDUuid
 
 	event := updatedEvent.ToEntity()
-	event, err = ctrl.eventService.UpdateEvent(event)
+	event, err = ctrl.eventService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).UpdateEvent(event, updatedEvent.MaxTicketsPerUser, updatedEvent.QueueEnabled, updatedEvent.QueueAdmitRate)
 	if err != nil {
 		log.Errorf("Failed to update event: %v", err)
 		utils.BadRequestResponse(c, "Failed to update event", err.Error())
@@ -204,6 +208,7 @@ func (ctrl *eventController) UpdateEvent(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security PartnerKeyAuth
 // @Param id path string true "Event ID"
 // @Success 200 {object} utils.Response
 // @Failure 401 {object} utils.Response
@@ -216,7 +221,7 @@ func (ctrl *eventController) DeleteEvent(c *gin.Context) {
 
 	id := c.Param("id")
 
-	if err := ctrl.eventService.DeleteEvent(id); err != nil {
+	if err := ctrl.eventService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).DeleteEvent(id); err != nil {
 		log.Errorf("Failed to delete event: %v", err)
 		utils.BadRequestResponse(c, "Failed to delete event", err.Error())
 		return
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"event-ticketing/utils"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportFormat membaca query format. Nilai selain json, csv, dan xlsx ditolak dengan 400.
+func exportFormat(c *gin.Context) (string, bool) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != utils.CSVFormat && format != utils.XLSXFormat {
+		utils.BadRequestResponse(c, "Invalid format", "format must be json, csv or xlsx")
+		return "", false
+	}
+	return format, true
+}
+
+// writeTable mengirim tabel sebagai file unduhan. Writer CSV dan XLSX menahan beberapa KB
+// pertama di buffer, jadi error yang terjadi sebelum ada data terkirim dikembalikan ke
+// pemanggil untuk dijadikan response error. Error setelahnya hanya bisa dicatat.
+func writeTable(c *gin.Context, format, name string, write func(w utils.TableWriter) error) error {
+	var log = utils.Log
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", utils.TableContentType(format))
+
+	w, err := utils.NewTableWriter(c.Writer, format, name)
+	if err == nil {
+		err = write(w)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+
+	if err != nil {
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			c.Header("Content-Type", "")
+			return err
+		}
+		log.Errorf("Failed to write %s export: %v", format, err)
+	}
+
+	return nil
+}
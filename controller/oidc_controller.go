@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"event-ticketing/dto"
+	"event-ticketing/service"
+	"event-ticketing/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OIDCController interface {
+	GetProviders(c *gin.Context)
+	StartLogin(c *gin.Context)
+	Callback(c *gin.Context)
+}
+
+type oidcController struct {
+	oidcService service.OIDCService
+}
+
+func NewOIDCController(oidcService service.OIDCService) OIDCController {
+	return &oidcController{
+		oidcService: oidcService,
+	}
+}
+
+// GetProviders godoc
+// @Summary List identity providers
+// @Description List the OpenID Connect providers that can be used to sign in
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} utils.Response
+// @Router /auth/oidc/providers [get]
+func (ctrl *oidcController) GetProviders(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Identity providers retrieved successfully", ctrl.oidcService.GetProviders())
+}
+
+// StartLogin godoc
+// @Summary Start OIDC login
+// @Description Get the authorization URL of an identity provider. The frontend redirects the user there and posts the returned code and state to the callback
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param X-Organization-ID header string true "Organization ID"
+// @Param provider path string true "Provider name"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Router /auth/oidc/{provider}/login [get]
+func (ctrl *oidcController) StartLogin(c *gin.Context) {
+	var log = utils.Log
+
+	authURL, err := ctrl.oidcService.WithTenant(c.GetString("organizationID")).StartLogin(c.Param("provider"))
+	if err != nil {
+		log.Errorf("Failed to start OIDC login: %v", err)
+		utils.BadRequestResponse(c, "Failed to start login", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Authorization URL created", gin.H{"authorization_url": authURL})
+}
+
+// Callback godoc
+// @Summary Complete OIDC login
+// @Description Exchange the authorization code returned by the identity provider for tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param request body dto.OIDCCallbackRequestDto true "Authorization code and state"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /auth/oidc/{provider}/callback [post]
+func (ctrl *oidcController) Callback(c *gin.Context) {
+	var log = utils.Log
+	var request dto.OIDCCallbackRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := ctrl.oidcService.WithActor(auditActor(c)).CompleteLogin(c.Param("provider"), request.Code, request.State)
+	if err != nil {
+		log.Errorf("OIDC login failed: %v", err)
+		utils.UnauthorizedResponse(c, "Login failed")
+		return
+	}
+
+	if result.TwoFactorRequired {
+		utils.SuccessResponse(c, http.StatusOK, "Two-factor authentication required", result)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Login successful", result)
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"event-ticketing/service"
+	"event-ticketing/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueueTokenHeader membawa token antrean saat mengecek posisi di ruang tunggu
+const QueueTokenHeader = "X-Queue-Token"
+
+type QueueController interface {
+	JoinQueue(c *gin.Context)
+	GetQueueStatus(c *gin.Context)
+}
+
+type queueController struct {
+	queueService service.QueueService
+}
+
+func NewQueueController(queueService service.QueueService) QueueController {
+	return &queueController{
+		queueService: queueService,
+	}
+}
+
+// JoinQueue godoc
+// @Summary Join the waiting room of an event
+// @Description Join the queue of an event that uses a waiting room. The returned token is only shown once; joining again issues a new token and keeps the current position
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security PartnerKeyAuth
+// @Param id path string true "Event ID"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Router /events/{id}/queue [post]
+func (ctrl *queueController) JoinQueue(c *gin.Context) {
+	var log = utils.Log
+
+	status, err := ctrl.queueService.WithTenant(c.GetString("organizationID")).JoinQueue(c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		log.Errorf("Failed to join queue: %v", err)
+		utils.BadRequestResponse(c, "Failed to join queue", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Joined queue successfully", status)
+}
+
+// GetQueueStatus godoc
+// @Summary Get waiting room position
+// @Description Get the current position in the waiting room. Once admitted, pass the token as queue_token when buying a ticket before expires_at
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security PartnerKeyAuth
+// @Param id path string true "Event ID"
+// @Param X-Queue-Token header string true "Queue token"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /events/{id}/queue [get]
+func (ctrl *queueController) GetQueueStatus(c *gin.Context) {
+	var log = utils.Log
+
+	status, err := ctrl.queueService.WithTenant(c.GetString("organizationID")).GetStatus(c.Param("id"), c.GetHeader(QueueTokenHeader))
+	if err != nil {
+		log.Errorf("Failed to get queue status: %v", err)
+		utils.NotFoundResponse(c, "Queue entry not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Queue status retrieved successfully", status)
+}
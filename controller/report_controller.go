@@ -1,25 +1,40 @@
 package controller
 
 import (
+	"event-ticketing/broker"
 	"event-ticketing/service"
 	"event-ticketing/utils"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
 )
 
 type ReportController interface {
 	GetSummaryReport(c *gin.Context)
 	GetEventReport(c *gin.Context)
+	GetEventSales(c *gin.Context)
+	StreamEventReport(c *gin.Context)
 }
 
+const (
+	// streamHeartbeatInterval menjaga koneksi SSE tetap hidup melewati proxy yang menutup koneksi diam
+	streamHeartbeatInterval = 15 * time.Second
+	// streamRecentMessages adalah jumlah ID pesan terakhir yang diingat untuk membuang pesan dobel
+	streamRecentMessages = 256
+)
+
 type reportController struct {
 	reportService service.ReportService
+	broker        broker.Broker
 }
 
-func NewReportController(reportService service.ReportService) ReportController {
+func NewReportController(reportService service.ReportService, broker broker.Broker) ReportController {
 	return &reportController{
 		reportService: reportService,
+		broker:        broker,
 	}
 }
 
@@ -29,8 +44,13 @@ func NewReportController(reportService service.ReportService) ReportController {
 // @Tags reports
 // @Accept json
 // @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Security ApiKeyAuth
+// @Security PartnerKeyAuth
+// @Param format query string false "Response format: json, csv or xlsx (default: json)"
 // @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 403 {object} utils.Response
 // @Failure 500 {object} utils.Response
@@ -38,13 +58,26 @@ func NewReportController(reportService service.ReportService) ReportController {
 func (ctrl *reportController) GetSummaryReport(c *gin.Context) {
 	var log = utils.Log
 
-	report, err := ctrl.reportService.GenerateSummaryReport()
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	report, err := ctrl.reportService.WithTenant(c.GetString("organizationID")).GenerateSummaryReport()
 	if err != nil {
 		log.Errorf("Failed to generate report: %v", err)
 		utils.InternalServerErrorResponse(c, "Failed to generate report", err.Error())
 		return
 	}
 
+	if format != "json" {
+		if err := writeTable(c, format, "summary-report", report.WriteTable); err != nil {
+			log.Errorf("Failed to export report: %v", err)
+			utils.InternalServerErrorResponse(c, "Failed to export report", err.Error())
+		}
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Report generated successfully", report)
 }
 
@@ -54,9 +87,14 @@ func (ctrl *reportController) GetSummaryReport(c *gin.Context) {
 // @Tags reports
 // @Accept json
 // @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Security ApiKeyAuth
+// @Security PartnerKeyAuth
 // @Param id path string true "Event ID"
+// @Param format query string false "Response format: json, csv or xlsx (default: json)"
 // @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 403 {object} utils.Response
 // @Failure 404 {object} utils.Response
@@ -65,13 +103,185 @@ func (ctrl *reportController) GetSummaryReport(c *gin.Context) {
 func (ctrl *reportController) GetEventReport(c *gin.Context) {
 	var log = utils.Log
 
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
 	eventID := c.Param("id")
-	report, err := ctrl.reportService.GenerateEventReport(eventID)
+	report, err := ctrl.reportService.WithTenant(c.GetString("organizationID")).GenerateEventReport(eventID)
 	if err != nil {
 		log.Errorf("Failed to generate report: %v", err)
 		utils.NotFoundResponse(c, "Event not found or failed to generate report")
 		return
 	}
 
+	if format != "json" {
+		if err := writeTable(c, format, "event-report", report.WriteTable); err != nil {
+			log.Errorf("Failed to export report: %v", err)
+			utils.InternalServerErrorResponse(c, "Failed to export report", err.Error())
+		}
+		return
+	}
+
 	utils.SuccessResponse(c, http.StatusOK, "Report generated successfully", report)
 }
+
+// GetEventSales godoc
+// @Summary Get sales over time for an event
+// @Description Get sold tickets, cancellations and net revenue per hour, day or week (weeks start on Monday), including cumulative totals (admin only)
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security PartnerKeyAuth
+// @Param id path string true "Event ID"
+// @Param interval query string false "Bucket size: hour, day or week (default: day)"
+// @Param tz query string false "IANA timezone for bucket boundaries, e.g. Asia/Jakarta (default: UTC)"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /reports/events/{id}/sales [get]
+func (ctrl *reportController) GetEventSales(c *gin.Context) {
+	var log = utils.Log
+
+	interval := service.SalesInterval(c.DefaultQuery("interval", string(service.DailySales)))
+	report, err := ctrl.reportService.WithTenant(c.GetString("organizationID")).GenerateSalesReport(c.Param("id"), interval, c.DefaultQuery("tz", "UTC"))
+	if err != nil {
+		log.Errorf("Failed to generate sales report: %v", err)
+		if err.Error() == "event not found" {
+			utils.NotFoundResponse(c, "Event not found")
+			return
+		}
+		utils.BadRequestResponse(c, "Failed to generate sales report", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Report generated successfully", report)
+}
+
+// StreamEventReport godoc
+// @Summary Stream live report for an event
+// @Description Server-Sent Events stream of the event report. A "report" event with the full report is sent on connect, and again whenever the server had to skip updates, so clients replace their state with it. Its no_show_list is always empty; fetch it from GET /reports/events/{id}. After that each change is sent as its own event (ticket.purchased, ticket.cancelled, ticket.checked_in, event.updated) carrying the current totals (total_tickets, sold_tickets, cancelled_tickets, revenue, checked_in) and the message_id of the change; clients overwrite those fields instead of adding them up. Updates never contain booking codes or user IDs. A comment line is sent every 15 seconds as heartbeat (admin only)
+// @Tags reports
+// @Produce text/event-stream
+// @Security ApiKeyAuth
+// @Security PartnerKeyAuth
+// @Param id path string true "Event ID"
+// @Success 200 {object} service.EventReport
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /reports/events/{id}/stream [get]
+func (ctrl *reportController) StreamEventReport(c *gin.Context) {
+	var log = utils.Log
+
+	eventID := c.Param("id")
+	reportService := ctrl.reportService.WithTenant(c.GetString("organizationID"))
+
+	// Subscribe sebelum laporan awal dibuat agar perubahan di antaranya tidak hilang
+	sub, err := ctrl.broker.Subscribe(service.EventTopic(eventID))
+	if err != nil {
+		log.Errorf("Failed to subscribe to event %s: %v", eventID, err)
+		utils.InternalServerErrorResponse(c, "Failed to open report stream", err.Error())
+		return
+	}
+	defer sub.Close()
+
+	// Laporan awal sekaligus memastikan event milik organisasi ini
+	report, err := reportService.GenerateEventReport(eventID)
+	if err != nil {
+		log.Errorf("Failed to generate report: %v", err)
+		utils.NotFoundResponse(c, "Event not found or failed to generate report")
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	c.SSEvent("report", streamReport(report))
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	// Setelah laporan awal hanya total dari broker yang diteruskan, sehingga laporan tidak
+	// dihitung ulang untuk setiap client yang terhubung. Total yang lebih lama dari data
+	// terakhir yang dikirim dan pesan yang dikirim ulang outbox dilewati.
+	lastGeneratedAt := report.GeneratedAt
+	recent := newRecentMessageIDs(streamRecentMessages)
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case message, ok := <-sub.Messages():
+			if !ok {
+				return
+			}
+
+			totals, ok := message.Data.(*service.EventReportTotals)
+			if !ok || !recent.add(totals.MessageID) || !totals.GeneratedAt.After(lastGeneratedAt) {
+				continue
+			}
+			lastGeneratedAt = totals.GeneratedAt
+
+			c.SSEvent(message.Type, totals)
+			c.Writer.Flush()
+		case <-sub.Dropped():
+			// Ada update yang terlewat, jadi kirim ulang laporan lengkap
+			report, err := reportService.GenerateEventReport(eventID)
+			if err != nil {
+				log.Errorf("Failed to regenerate report for event %s: %v", eventID, err)
+				return
+			}
+			lastGeneratedAt = report.GeneratedAt
+
+			c.SSEvent("report", streamReport(report))
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// streamReport membuang daftar no-show dari laporan stream karena berisi kode booking dan
+// data pemegang tiket. Daftar lengkap tetap tersedia di endpoint laporan event.
+func streamReport(report *service.EventReport) *service.EventReport {
+	report.Attendance.NoShowList = []service.NoShow{}
+	return report
+}
+
+// recentMessageIDs mengingat sejumlah ID pesan terakhir untuk membuang pesan dobel
+type recentMessageIDs struct {
+	ids   map[uuid.UUID]struct{}
+	order []uuid.UUID
+	limit int
+}
+
+func newRecentMessageIDs(limit int) *recentMessageIDs {
+	return &recentMessageIDs{ids: map[uuid.UUID]struct{}{}, limit: limit}
+}
+
+// add mencatat id dan mengembalikan false jika id sudah pernah dicatat
+func (r *recentMessageIDs) add(id uuid.UUID) bool {
+	if _, ok := r.ids[id]; ok {
+		return false
+	}
+
+	if len(r.order) == r.limit {
+		delete(r.ids, r.order[0])
+		r.order = r.order[1:]
+	}
+	r.ids[id] = struct{}{}
+	r.order = append(r.order, id)
+	return true
+}
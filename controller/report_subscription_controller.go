@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"event-ticketing/dto"
+	"event-ticketing/service"
+	"event-ticketing/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportSubscriptionController interface {
+	CreateSubscription(c *gin.Context)
+	GetAllSubscriptions(c *gin.Context)
+	DeleteSubscription(c *gin.Context)
+}
+
+type reportSubscriptionController struct {
+	subscriptionService service.ReportSubscriptionService
+}
+
+func NewReportSubscriptionController(subscriptionService service.ReportSubscriptionService) ReportSubscriptionController {
+	return &reportSubscriptionController{
+		subscriptionService: subscriptionService,
+	}
+}
+
+// CreateSubscription godoc
+// @Summary Subscribe to a scheduled report
+// @Description Email a summary, event or daily sales report on a cron schedule (minute hour day month weekday, or @hourly, @daily, @weekly, @monthly) evaluated in the given timezone. CSV reports are attached, HTML reports are sent as the email body (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dto.CreateReportSubscriptionRequestDto true "Report subscription data"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/report-subscriptions [post]
+func (ctrl *reportSubscriptionController) CreateSubscription(c *gin.Context) {
+	var log = utils.Log
+	var request dto.CreateReportSubscriptionRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	subscription := request.ToEntity()
+	err := ctrl.subscriptionService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).CreateSubscription(subscription)
+	if err != nil {
+		log.Errorf("Failed to create report subscription: %v", err)
+		utils.BadRequestResponse(c, "Failed to create report subscription", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Report subscription created successfully", subscription)
+}
+
+// GetAllSubscriptions godoc
+// @Summary List report subscriptions
+// @Description List scheduled report subscriptions of the organization with their next run and last error (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query string false "Page number (default: 1)"
+// @Param limit query string false "Results per page (default: 10)"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/report-subscriptions [get]
+func (ctrl *reportSubscriptionController) GetAllSubscriptions(c *gin.Context) {
+	var log = utils.Log
+
+	params := utils.NewPaginationParams(c.DefaultQuery("page", "1"), c.DefaultQuery("limit", "10"))
+
+	subscriptions, totalItems, err := ctrl.subscriptionService.WithTenant(c.GetString("organizationID")).GetAllSubscriptions(params)
+	if err != nil {
+		log.Errorf("Failed to retrieve report subscriptions: %v", err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve report subscriptions", err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Report subscriptions retrieved successfully", subscriptions, totalItems, params.Page, params.Limit)
+}
+
+// DeleteSubscription godoc
+// @Summary Delete a report subscription
+// @Description Stop sending a scheduled report (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Report subscription ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/report-subscriptions/{id} [delete]
+func (ctrl *reportSubscriptionController) DeleteSubscription(c *gin.Context) {
+	var log = utils.Log
+
+	err := ctrl.subscriptionService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).DeleteSubscription(c.Param("id"))
+	if err != nil {
+		log.Errorf("Failed to delete report subscription: %v", err)
+		if err.Error() == "report subscription not found" {
+			utils.NotFoundResponse(c, "Report subscription not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to delete report subscription", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Report subscription deleted successfully", nil)
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"event-ticketing/dto"
 	"event-ticketing/service"
 	"event-ticketing/utils"
@@ -16,6 +17,7 @@ type TicketController interface {
 	GetUserTickets(c *gin.Context)
 	GetEventTickets(c *gin.Context)
 	CancelTicket(c *gin.Context)
+	CheckIn(c *gin.Context)
 }
 
 type ticketController struct {
@@ -35,10 +37,13 @@ func NewTicketController(ticketService service.TicketService) TicketController {
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security PartnerKeyAuth
+// @Param X-On-Behalf-Of header string false "User ID to buy for (API keys with users:on_behalf scope only)"
 // @Param ticket body dto.BuyTicketRequest true "Ticket purchase info"
 // @Success 201 {object} utils.Response
 // @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
 // @Failure 409 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /tickets [post]
@@ -76,9 +81,13 @@ func (ctrl *ticketController) BuyTicket(c *gin.Context) {
 
 	ticket.UserID = userUUID
 
-	ticket, err = ctrl.ticketService.BuyTicket(ticket)
+	ticket, err = ctrl.ticketService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).BuyTicket(ticket)
 	if err != nil {
 		log.Errorf("Failed to buy ticket: %v", err)
+		if errors.Is(err, service.ErrQueueAdmissionRequired) {
+			utils.ForbiddenResponse(c, err.Error())
+			return
+		}
 		utils.BadRequestResponse(c, "Failed to buy ticket", err.Error())
 		return
 	}
@@ -93,6 +102,7 @@ func (ctrl *ticketController) BuyTicket(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security PartnerKeyAuth
 // @Param id path string true "Ticket ID"
 // @Success 200 {object} utils.Response
 // @Failure 401 {object} utils.Response
@@ -109,7 +119,7 @@ func (ctrl *ticketController) GetTicketByID(c *gin.Context) {
 		return
 	}
 
-	ticket, err := ctrl.ticketService.GetTicketByID(ticketIDStr)
+	ticket, err := ctrl.ticketService.WithTenant(c.GetString("organizationID")).GetTicketByID(ticketIDStr)
 	if err != nil {
 		log.Errorf("Failed to get ticket: %v", err)
 		utils.NotFoundResponse(c, "Ticket not found")
@@ -126,6 +136,7 @@ func (ctrl *ticketController) GetTicketByID(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security PartnerKeyAuth
 // @Param page query string false "Page number (default: 1)"
 // @Param limit query string false "Results per page (default: 10)"
 // @Success 200 {object} utils.Response
@@ -144,7 +155,7 @@ func (ctrl *ticketController) GetUserTickets(c *gin.Context) {
 
 	params := utils.NewPaginationParams(c.DefaultQuery("page", "1"), c.DefaultQuery("limit", "10"))
 
-	tickets, totalItems, err := ctrl.ticketService.GetTicketsByUserID(userID.(string), params)
+	tickets, totalItems, err := ctrl.ticketService.WithTenant(c.GetString("organizationID")).GetTicketsByUserID(userID.(string), params)
 	if err != nil {
 		log.Errorf("Failed to retrieve tickets: %v", err)
 		utils.InternalServerErrorResponse(c, "Failed to retrieve tickets", err.Error())
@@ -156,26 +167,54 @@ func (ctrl *ticketController) GetUserTickets(c *gin.Context) {
 
 // GetEventTickets godoc
 // @Summary Get all tickets for an event
-// @Description Get all tickets for a specific event (admin only)
+// @Description Get all tickets for a specific event, or download the attendee list as CSV or XLSX (admin only)
 // @Tags tickets
 // @Accept json
 // @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Security ApiKeyAuth
+// @Security PartnerKeyAuth
 // @Param id path string true "Event ID"
 // @Param page query string false "Page number (default: 1)"
 // @Param limit query string false "Results per page (default: 10)"
+// @Param format query string false "Response format: json, csv or xlsx (default: json). csv and xlsx return the full attendee list without pagination"
 // @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
 // @Failure 401 {object} utils.Response
 // @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
 // @Failure 500 {object} utils.Response
 // @Router /events/{id}/tickets [get]
 func (ctrl *ticketController) GetEventTickets(c *gin.Context) {
 	var log = utils.Log
 
 	eventID := c.Param("id")
+
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	if format != "json" {
+		ticketService := ctrl.ticketService.WithTenant(c.GetString("organizationID"))
+		err := writeTable(c, format, "attendees", func(w utils.TableWriter) error {
+			return ticketService.ExportEventTickets(eventID, w)
+		})
+		if err != nil {
+			log.Errorf("Failed to export tickets: %v", err)
+			if err.Error() == "event not found" {
+				utils.NotFoundResponse(c, "Event not found")
+				return
+			}
+			utils.InternalServerErrorResponse(c, "Failed to export tickets", err.Error())
+		}
+		return
+	}
+
 	params := utils.NewPaginationParams(c.DefaultQuery("page", "1"), c.DefaultQuery("limit", "10"))
 
-	tickets, totalItems, err := ctrl.ticketService.GetTicketsByEventID(eventID, params)
+	tickets, totalItems, err := ctrl.ticketService.WithTenant(c.GetString("organizationID")).GetTicketsByEventID(eventID, params)
 	if err != nil {
 		log.Errorf("Failed to retrieve tickets: %v", err)
 		utils.InternalServerErrorResponse(c, "Failed to retrieve tickets", err.Error())
@@ -192,6 +231,7 @@ func (ctrl *ticketController) GetEventTickets(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
+// @Security PartnerKeyAuth
 // @Param id path string true "Ticket ID"
 // @Success 200 {object} utils.Response
 // @Failure 400 {object} utils.Response
@@ -205,7 +245,7 @@ func (ctrl *ticketController) CancelTicket(c *gin.Context) {
 
 	id := c.Param("id")
 
-	if err := ctrl.ticketService.CancelTicket(id); err != nil {
+	if err := ctrl.ticketService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).CancelTicket(id); err != nil {
 		log.Errorf("Failed to cancel ticket: %v", err)
 		utils.BadRequestResponse(c, "Failed to cancel ticket", err.Error())
 		return
@@ -213,3 +253,42 @@ func (ctrl *ticketController) CancelTicket(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "Ticket cancelled successfully", nil)
 }
+
+// CheckIn godoc
+// @Summary Check in a ticket
+// @Description Record that the ticket holder arrived at the event through a gate. A ticket can only be checked in once (admin only)
+// @Tags tickets
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Security PartnerKeyAuth
+// @Param request body dto.CheckInRequestDto true "Booking code and gate"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /tickets/check-in [post]
+func (ctrl *ticketController) CheckIn(c *gin.Context) {
+	var log = utils.Log
+
+	var request dto.CheckInRequestDto
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	ticket, err := ctrl.ticketService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).CheckIn(request.BookingCode, request.Gate)
+	if err != nil {
+		log.Errorf("Failed to check in ticket: %v", err)
+		if err.Error() == "ticket not found" {
+			utils.NotFoundResponse(c, "Ticket not found")
+			return
+		}
+		utils.BadRequestResponse(c, "Failed to check in ticket", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ticket checked in successfully", ticket)
+}
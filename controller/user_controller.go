@@ -0,0 +1,293 @@
+package controller
+
+import (
+	"event-ticketing/service"
+	"event-ticketing/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserController interface {
+	GetAllUsers(c *gin.Context)
+	GetUserByID(c *gin.Context)
+	PromoteUser(c *gin.Context)
+	DemoteUser(c *gin.Context)
+	SuspendUser(c *gin.Context)
+	UnsuspendUser(c *gin.Context)
+	UnlockUser(c *gin.Context)
+	DeleteUser(c *gin.Context)
+	GetUserNotifications(c *gin.Context)
+}
+
+type userController struct {
+	userService service.UserService
+}
+
+func NewUserController(userService service.UserService) UserController {
+	return &userController{
+		userService: userService,
+	}
+}
+
+// GetAllUsers godoc
+// @Summary List users
+// @Description List and search users of the organization (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query string false "Page number (default: 1)"
+// @Param limit query string false "Results per page (default: 10)"
+// @Param keyword query string false "Search by name or email"
+// @Param role query string false "Role filter (admin/user)"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/users [get]
+func (ctrl *userController) GetAllUsers(c *gin.Context) {
+	var log = utils.Log
+
+	params := utils.NewPaginationParams(c.DefaultQuery("page", "1"), c.DefaultQuery("limit", "10"))
+
+	users, totalItems, err := ctrl.userService.WithTenant(c.GetString("organizationID")).GetAllUsers(params, c.Query("keyword"), c.Query("role"))
+	if err != nil {
+		log.Errorf("Failed to retrieve users: %v", err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve users", err.Error())
+		return
+	}
+
+	for i := range users {
+		users[i].Password = ""
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Users retrieved successfully", users, totalItems, params.Page, params.Limit)
+}
+
+// GetUserByID godoc
+// @Summary Get a user by ID
+// @Description Get a user of the organization by ID (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/users/{id} [get]
+func (ctrl *userController) GetUserByID(c *gin.Context) {
+	var log = utils.Log
+
+	user, err := ctrl.userService.WithTenant(c.GetString("organizationID")).GetUserByID(c.Param("id"))
+	if err != nil {
+		log.Errorf("User not found: %v", err)
+		utils.NotFoundResponse(c, "User not found")
+		return
+	}
+
+	user.Password = ""
+
+	utils.SuccessResponse(c, http.StatusOK, "User retrieved successfully", user)
+}
+
+// PromoteUser godoc
+// @Summary Promote a user to admin
+// @Description Give a user the admin role (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/users/{id}/promote [put]
+func (ctrl *userController) PromoteUser(c *gin.Context) {
+	var log = utils.Log
+
+	user, err := ctrl.userService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).PromoteUser(c.Param("id"))
+	if err != nil {
+		log.Errorf("Failed to promote user: %v", err)
+		utils.BadRequestResponse(c, "Failed to promote user", err.Error())
+		return
+	}
+
+	user.Password = ""
+
+	utils.SuccessResponse(c, http.StatusOK, "User promoted successfully", user)
+}
+
+// DemoteUser godoc
+// @Summary Demote an admin to user
+// @Description Remove the admin role from a user (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/users/{id}/demote [put]
+func (ctrl *userController) DemoteUser(c *gin.Context) {
+	var log = utils.Log
+
+	user, err := ctrl.userService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).DemoteUser(c.Param("id"))
+	if err != nil {
+		log.Errorf("Failed to demote user: %v", err)
+		utils.BadRequestResponse(c, "Failed to demote user", err.Error())
+		return
+	}
+
+	user.Password = ""
+
+	utils.SuccessResponse(c, http.StatusOK, "User demoted successfully", user)
+}
+
+// SuspendUser godoc
+// @Summary Suspend a user
+// @Description Block a user from logging in and revoke their sessions (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/users/{id}/suspend [put]
+func (ctrl *userController) SuspendUser(c *gin.Context) {
+	var log = utils.Log
+
+	user, err := ctrl.userService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).SuspendUser(c.Param("id"))
+	if err != nil {
+		log.Errorf("Failed to suspend user: %v", err)
+		utils.BadRequestResponse(c, "Failed to suspend user", err.Error())
+		return
+	}
+
+	user.Password = ""
+
+	utils.SuccessResponse(c, http.StatusOK, "User suspended successfully", user)
+}
+
+// UnsuspendUser godoc
+// @Summary Unsuspend a user
+// @Description Allow a suspended user to log in again (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/users/{id}/unsuspend [put]
+func (ctrl *userController) UnsuspendUser(c *gin.Context) {
+	var log = utils.Log
+
+	user, err := ctrl.userService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).UnsuspendUser(c.Param("id"))
+	if err != nil {
+		log.Errorf("Failed to unsuspend user: %v", err)
+		utils.BadRequestResponse(c, "Failed to unsuspend user", err.Error())
+		return
+	}
+
+	user.Password = ""
+
+	utils.SuccessResponse(c, http.StatusOK, "User unsuspended successfully", user)
+}
+
+// UnlockUser godoc
+// @Summary Unlock a user
+// @Description Clear failed login attempts and lift a temporary account lockout (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/users/{id}/unlock [put]
+func (ctrl *userController) UnlockUser(c *gin.Context) {
+	var log = utils.Log
+
+	user, err := ctrl.userService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).UnlockUser(c.Param("id"))
+	if err != nil {
+		log.Errorf("Failed to unlock user: %v", err)
+		utils.BadRequestResponse(c, "Failed to unlock user", err.Error())
+		return
+	}
+
+	user.Password = ""
+
+	utils.SuccessResponse(c, http.StatusOK, "User unlocked successfully", user)
+}
+
+// DeleteUser godoc
+// @Summary Delete a user
+// @Description Delete a user and revoke their sessions (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/users/{id} [delete]
+func (ctrl *userController) DeleteUser(c *gin.Context) {
+	var log = utils.Log
+
+	if err := ctrl.userService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).DeleteUser(c.Param("id")); err != nil {
+		log.Errorf("Failed to delete user: %v", err)
+		utils.BadRequestResponse(c, "Failed to delete user", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User deleted successfully", nil)
+}
+
+// GetUserNotifications godoc
+// @Summary List notifications of a user
+// @Description List emails sent to a user with their delivery status, attempts and last error (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Param page query string false "Page number (default: 1)"
+// @Param limit query string false "Results per page (default: 10)"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/users/{id}/notifications [get]
+func (ctrl *userController) GetUserNotifications(c *gin.Context) {
+	var log = utils.Log
+
+	params := utils.NewPaginationParams(c.DefaultQuery("page", "1"), c.DefaultQuery("limit", "10"))
+
+	notifications, totalItems, err := ctrl.userService.WithTenant(c.GetString("organizationID")).GetUserNotifications(c.Param("id"), params)
+	if err != nil {
+		log.Errorf("Failed to retrieve notifications: %v", err)
+		if err.Error() == "user not found" {
+			utils.NotFoundResponse(c, "User not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to retrieve notifications", err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Notifications retrieved successfully", notifications, totalItems, params.Page, params.Limit)
+}
@@ -0,0 +1,218 @@
+package controller
+
+import (
+	"event-ticketing/dto"
+	"event-ticketing/repository"
+	"event-ticketing/service"
+	"event-ticketing/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController interface {
+	CreateWebhook(c *gin.Context)
+	GetAllWebhooks(c *gin.Context)
+	DeleteWebhook(c *gin.Context)
+	GetDeliveries(c *gin.Context)
+	GetDelivery(c *gin.Context)
+	ReplayDelivery(c *gin.Context)
+}
+
+type webhookController struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookController(webhookService service.WebhookService) WebhookController {
+	return &webhookController{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Register a webhook
+// @Description Send ticket.purchased, ticket.cancelled, ticket.checked_in, event.created, event.updated or event.deleted to a partner URL. The URL must resolve to a public address and use https outside development. Each request is signed with HMAC-SHA256 over "<X-Webhook-Timestamp>.<body>" in the X-Webhook-Signature header. The secret is generated when omitted and only returned once (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dto.CreateWebhookRequestDto true "Webhook data"
+// @Success 201 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Router /admin/webhooks [post]
+func (ctrl *webhookController) CreateWebhook(c *gin.Context) {
+	var log = utils.Log
+	var request dto.CreateWebhookRequestDto
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Errorf("Failed to bind JSON: %v", err)
+		utils.BadRequestResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	webhook, err := ctrl.webhookService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).
+		CreateSubscription(request.ToEntity(), request.Secret)
+	if err != nil {
+		log.Errorf("Failed to create webhook: %v", err)
+		utils.BadRequestResponse(c, "Failed to create webhook", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Webhook created successfully", webhook)
+}
+
+// GetAllWebhooks godoc
+// @Summary List webhooks
+// @Description List webhook subscriptions of the organization (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query string false "Page number (default: 1)"
+// @Param limit query string false "Results per page (default: 10)"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/webhooks [get]
+func (ctrl *webhookController) GetAllWebhooks(c *gin.Context) {
+	var log = utils.Log
+
+	params := utils.NewPaginationParams(c.DefaultQuery("page", "1"), c.DefaultQuery("limit", "10"))
+
+	webhooks, totalItems, err := ctrl.webhookService.WithTenant(c.GetString("organizationID")).GetAllSubscriptions(params)
+	if err != nil {
+		log.Errorf("Failed to retrieve webhooks: %v", err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve webhooks", err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Webhooks retrieved successfully", webhooks, totalItems, params.Page, params.Limit)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Stop sending events to a webhook. Deliveries still queued are moved to the dead-letter queue (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/webhooks/{id} [delete]
+func (ctrl *webhookController) DeleteWebhook(c *gin.Context) {
+	var log = utils.Log
+
+	err := ctrl.webhookService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).DeleteSubscription(c.Param("id"))
+	if err != nil {
+		log.Errorf("Failed to delete webhook: %v", err)
+		if err.Error() == "webhook subscription not found" {
+			utils.NotFoundResponse(c, "Webhook not found")
+			return
+		}
+		utils.InternalServerErrorResponse(c, "Failed to delete webhook", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook deleted successfully", nil)
+}
+
+// GetDeliveries godoc
+// @Summary List webhook deliveries
+// @Description Inspect webhook deliveries with their payload and last response, newest first. Use status=dead to list the dead-letter queue (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query string false "Page number (default: 1)"
+// @Param limit query string false "Results per page (default: 10)"
+// @Param subscription_id query string false "Webhook ID"
+// @Param status query string false "Status: pending, delivered or dead"
+// @Param event_type query string false "Event type, e.g. ticket.purchased"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 500 {object} utils.Response
+// @Router /admin/webhook-deliveries [get]
+func (ctrl *webhookController) GetDeliveries(c *gin.Context) {
+	var log = utils.Log
+
+	params := utils.NewPaginationParams(c.DefaultQuery("page", "1"), c.DefaultQuery("limit", "10"))
+	filter := repository.WebhookDeliveryFilter{
+		SubscriptionID: c.Query("subscription_id"),
+		Status:         c.Query("status"),
+		EventType:      c.Query("event_type"),
+	}
+
+	deliveries, totalItems, err := ctrl.webhookService.WithTenant(c.GetString("organizationID")).GetDeliveries(params, filter)
+	if err != nil {
+		log.Errorf("Failed to retrieve webhook deliveries: %v", err)
+		utils.InternalServerErrorResponse(c, "Failed to retrieve webhook deliveries", err.Error())
+		return
+	}
+
+	utils.PaginatedResponse(c, http.StatusOK, "Webhook deliveries retrieved successfully", deliveries, totalItems, params.Page, params.Limit)
+}
+
+// GetDelivery godoc
+// @Summary Get a webhook delivery
+// @Description Get a webhook delivery with its payload, attempts and last response (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook delivery ID"
+// @Success 200 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/webhook-deliveries/{id} [get]
+func (ctrl *webhookController) GetDelivery(c *gin.Context) {
+	var log = utils.Log
+
+	delivery, err := ctrl.webhookService.WithTenant(c.GetString("organizationID")).GetDelivery(c.Param("id"))
+	if err != nil {
+		log.Errorf("Failed to retrieve webhook delivery: %v", err)
+		utils.NotFoundResponse(c, "Webhook delivery not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook delivery retrieved successfully", delivery)
+}
+
+// ReplayDelivery godoc
+// @Summary Replay a webhook delivery
+// @Description Send a delivered or dead-lettered webhook again with the same payload and ID, restarting its retries (admin only)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Webhook delivery ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.Response
+// @Failure 401 {object} utils.Response
+// @Failure 403 {object} utils.Response
+// @Failure 404 {object} utils.Response
+// @Router /admin/webhook-deliveries/{id}/replay [post]
+func (ctrl *webhookController) ReplayDelivery(c *gin.Context) {
+	var log = utils.Log
+
+	delivery, err := ctrl.webhookService.WithTenant(c.GetString("organizationID")).WithActor(auditActor(c)).ReplayDelivery(c.Param("id"))
+	if err != nil {
+		log.Errorf("Failed to replay webhook delivery: %v", err)
+		if err.Error() == "webhook delivery not found" {
+			utils.NotFoundResponse(c, "Webhook delivery not found")
+			return
+		}
+		utils.BadRequestResponse(c, "Failed to replay webhook delivery", err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Webhook delivery queued for replay", delivery)
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// Envelope membungkus domain event dengan data baris outbox-nya. ID tetap sama setiap
+// kali pesan yang sama dikirim ulang sehingga handler bisa membuang duplikat.
+type Envelope struct {
+	ID             uuid.UUID
+	OrganizationID uuid.UUID
+	OccurredAt     time.Time
+	Event          Event
+}
+
+// Handler memproses satu domain event. Error membuat relay mengirim ulang pesan yang
+// sama nanti, jadi handler harus aman dipanggil lebih dari sekali.
+type Handler func(envelope Envelope) error
+
+// Bus mengirim domain event ke handler di dalam proses ini secara sinkron. Berbeda dengan
+// broker.Broker yang membuang pesan untuk subscriber lambat, Publish baru berhasil jika
+// semua handler berhasil.
+type Bus interface {
+	Subscribe(eventType string, handler Handler)
+	Publish(envelope Envelope) error
+}
+
+type memoryBus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewBus() Bus {
+	return &memoryBus{handlers: map[string][]Handler{}}
+}
+
+func (b *memoryBus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish memanggil handler sesuai urutan subscribe dan berhenti di handler pertama yang gagal
+func (b *memoryBus) Publish(envelope Envelope) (err error) {
+	b.mu.RLock()
+	handlers := b.handlers[envelope.Event.EventType()]
+	b.mu.RUnlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("domain event handler panicked: %v", r)
+		}
+	}()
+
+	for _, handler := range handlers {
+		if err := handler(envelope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
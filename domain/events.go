@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"encoding/json"
+	"event-ticketing/entity"
+	"fmt"
+)
+
+const (
+	TicketPurchasedType = "ticket.purchased"
+	TicketCancelledType = "ticket.cancelled"
+	TicketCheckedInType = "ticket.checked_in"
+	EventCreatedType    = "event.created"
+	EventUpdatedType    = "event.updated"
+	EventDeletedType    = "event.deleted"
+)
+
+// Event adalah domain event yang ditulis ke outbox dalam transaksi yang sama dengan
+// perubahan datanya, lalu dikirim ke Bus setelah transaksi commit
+type Event interface {
+	EventType() string
+}
+
+type TicketPurchased struct {
+	Ticket entity.Ticket `json:"ticket"`
+}
+
+type TicketCancelled struct {
+	Ticket entity.Ticket `json:"ticket"`
+}
+
+type TicketCheckedIn struct {
+	Ticket entity.Ticket `json:"ticket"`
+}
+
+type EventCreated struct {
+	Event entity.Event `json:"event"`
+}
+
+type EventUpdated struct {
+	Before entity.Event `json:"before"`
+	After  entity.Event `json:"after"`
+}
+
+type EventDeleted struct {
+	Event entity.Event `json:"event"`
+}
+
+func (TicketPurchased) EventType() string { return TicketPurchasedType }
+func (TicketCancelled) EventType() string { return TicketCancelledType }
+func (TicketCheckedIn) EventType() string { return TicketCheckedInType }
+func (EventCreated) EventType() string    { return EventCreatedType }
+func (EventUpdated) EventType() string    { return EventUpdatedType }
+func (EventDeleted) EventType() string    { return EventDeletedType }
+
+var registry = map[string]func() Event{
+	TicketPurchasedType: func() Event { return &TicketPurchased{} },
+	TicketCancelledType: func() Event { return &TicketCancelled{} },
+	TicketCheckedInType: func() Event { return &TicketCheckedIn{} },
+	EventCreatedType:    func() Event { return &EventCreated{} },
+	EventUpdatedType:    func() Event { return &EventUpdated{} },
+	EventDeletedType:    func() Event { return &EventDeleted{} },
+}
+
+// Decode membaca payload outbox kembali menjadi domain event bertipe. Hasilnya selalu
+// berupa pointer, misalnya *TicketPurchased.
+func Decode(eventType string, payload []byte) (Event, error) {
+	newEvent, ok := registry[eventType]
+	if !ok {
+		return nil, fmt.Errorf("unknown domain event type: %s", eventType)
+	}
+
+	event := newEvent()
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
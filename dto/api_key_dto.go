@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+type CreateAPIKeyRequestDto struct {
+	Name      string     `json:"name" binding:"required"`
+	UserID    string     `json:"user_id" binding:"omitempty,uuid"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -7,41 +7,51 @@ import (
 )
 
 type CreateEventReqDto struct {
-	Name        string    `json:"name" binding:"required"`
-	Description string    `json:"description"`
-	StartDate   time.Time `json:"start_date" binding:"required"`
-	EndDate     time.Time `json:"end_date" binding:"required"`
-	Capacity    int       `json:"capacity" binding:"required,min=1"`
-	Price       float64   `json:"price" binding:"required,min=0"`
-	Status      string    `json:"status" binding:"required,oneof=active ongoing completed"`
-	Location    string    `json:"location" binding:"required"`
-	CreatedBy   uuid.UUID `json:"-"`
+	Name              string    `json:"name" binding:"required"`
+	Description       string    `json:"description"`
+	StartDate         time.Time `json:"start_date" binding:"required"`
+	EndDate           time.Time `json:"end_date" binding:"required"`
+	Capacity          int       `json:"capacity" binding:"required,min=1"`
+	Price             float64   `json:"price" binding:"required,min=0"`
+	Status            string    `json:"status" binding:"required,oneof=active ongoing completed"`
+	Location          string    `json:"location" binding:"required"`
+	MaxTicketsPerUser int       `json:"max_tickets_per_user" binding:"min=0"`
+	QueueEnabled      bool      `json:"queue_enabled"`
+	QueueAdmitRate    int       `json:"queue_admit_rate" binding:"min=0"`
+	CreatedBy         uuid.UUID `json:"-"`
 }
 
 func (e *CreateEventReqDto) ToEntity() *entity.Event {
 	return &entity.Event{
-		Name:        e.Name,
-		Description: e.Description,
-		StartDate:   e.StartDate,
-		EndDate:     e.EndDate,
-		Capacity:    e.Capacity,
-		Price:       e.Price,
-		Status:      entity.EventStatus(e.Status),
-		Location:    e.Location,
-		CreatedBy:   e.CreatedBy,
+		Name:              e.Name,
+		Description:       e.Description,
+		StartDate:         e.StartDate,
+		EndDate:           e.EndDate,
+		Capacity:          e.Capacity,
+		Price:             e.Price,
+		Status:            entity.EventStatus(e.Status),
+		Location:          e.Location,
+		MaxTicketsPerUser: e.MaxTicketsPerUser,
+		QueueEnabled:      e.QueueEnabled,
+		QueueAdmitRate:    e.QueueAdmitRate,
+		CreatedBy:         e.CreatedBy,
 	}
 }
 
+// UpdateEventReqDto mengganti data event. Field pointer yang dibiarkan kosong tidak mengubah nilai yang tersimpan.
 type UpdateEventReqDto struct {
-	ID          uuid.UUID `json:"-"`
-	Name        string    `json:"name" binding:"required"`
-	Description string    `json:"description"`
-	StartDate   time.Time `json:"start_date" binding:"required"`
-	EndDate     time.Time `json:"end_date" binding:"required"`
-	Capacity    int       `json:"capacity" binding:"required,min=1"`
-	Price       float64   `json:"price" binding:"required,min=0"`
-	Status      string    `json:"status" binding:"required,oneof=active ongoing completed"`
-	Location    string    `json:"location" binding:"required"`
+	ID                uuid.UUID `json:"-"`
+	Name              string    `json:"name" binding:"required"`
+	Description       string    `json:"description"`
+	StartDate         time.Time `json:"start_date" binding:"required"`
+	EndDate           time.Time `json:"end_date" binding:"required"`
+	Capacity          int       `json:"capacity" binding:"required,min=1"`
+	Price             float64   `json:"price" binding:"required,min=0"`
+	Status            string    `json:"status" binding:"required,oneof=active ongoing completed"`
+	Location          string    `json:"location" binding:"required"`
+	MaxTicketsPerUser *int      `json:"max_tickets_per_user" binding:"omitempty,min=0"`
+	QueueEnabled      *bool     `json:"queue_enabled"`
+	QueueAdmitRate    *int      `json:"queue_admit_rate" binding:"omitempty,min=0"`
 }
 
 func (e *UpdateEventReqDto) ToEntity() *entity.Event {
@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"event-ticketing/entity"
+	"github.com/gofrs/uuid/v5"
+	"strings"
+)
+
+type CreateReportSubscriptionRequestDto struct {
+	Name       string     `json:"name" binding:"required,max=255"`
+	ReportType string     `json:"report_type" binding:"required,oneof=summary event sales"`
+	EventID    *uuid.UUID `json:"event_id"`
+	Schedule   string     `json:"schedule" binding:"required"`
+	Timezone   string     `json:"timezone"`
+	Recipients []string   `json:"recipients" binding:"required,min=1,dive,email"`
+	Format     string     `json:"format" binding:"required,oneof=csv html"`
+}
+
+func (r *CreateReportSubscriptionRequestDto) ToEntity() *entity.ReportSubscription {
+	timezone := r.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	return &entity.ReportSubscription{
+		Name:       r.Name,
+		ReportType: entity.ReportType(r.ReportType),
+		EventID:    r.EventID,
+		Schedule:   strings.TrimSpace(r.Schedule),
+		Timezone:   timezone,
+		Recipients: strings.Join(r.Recipients, ","),
+		Format:     entity.ReportFormat(r.Format),
+	}
+}
@@ -9,11 +9,18 @@ import (
 type BuyTicketRequest struct {
 	EventID      uuid.UUID `json:"event_id" binding:"required"`
 	PurchaseDate time.Time `json:"purchase_date"`
+	QueueToken   string    `json:"queue_token"`
 }
 
 func (b *BuyTicketRequest) ToEntity() *entity.Ticket {
 	return &entity.Ticket{
 		EventID:      b.EventID,
 		PurchaseDate: b.PurchaseDate,
+		QueueToken:   b.QueueToken,
 	}
 }
+
+type CheckInRequestDto struct {
+	BookingCode string `json:"booking_code" binding:"required"`
+	Gate        string `json:"gate" binding:"max=50"`
+}
@@ -1,6 +1,74 @@
 package dto
 
+import "event-ticketing/entity"
+
+type RegisterRequestDto struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+	Language string `json:"language" binding:"omitempty,oneof=id en"`
+}
+
+// ToEntity selalu membuat user biasa, role tidak pernah diambil dari request
+func (r *RegisterRequestDto) ToEntity() *entity.User {
+	return &entity.User{
+		Name:     r.Name,
+		Email:    r.Email,
+		Password: r.Password,
+		Role:     entity.UserRole,
+		Language: entity.Language(r.Language),
+	}
+}
+
 type UserRequestDto struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+type RefreshTokenRequestDto struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type ForgotPasswordRequestDto struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequestDto struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type VerifyEmailRequestDto struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type TwoFactorLoginRequestDto struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+type TwoFactorCodeRequestDto struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type UpdateProfileRequestDto struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"omitempty,email"`
+	Language string `json:"language" binding:"omitempty,oneof=id en"`
+	// EventRemindersOptOut dibiarkan kosong agar pengaturan pengingat tidak berubah
+	EventRemindersOptOut *bool `json:"event_reminders_opt_out"`
+}
+
+type ChangePasswordRequestDto struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+type DeleteAccountRequestDto struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type OIDCCallbackRequestDto struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
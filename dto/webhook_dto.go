@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"event-ticketing/entity"
+	"strings"
+)
+
+type CreateWebhookRequestDto struct {
+	Name       string   `json:"name" binding:"required,max=255"`
+	URL        string   `json:"url" binding:"required,url,max=2048"`
+	Secret     string   `json:"secret" binding:"omitempty,min=16"`
+	EventTypes []string `json:"event_types" binding:"required,min=1"`
+}
+
+func (r *CreateWebhookRequestDto) ToEntity() *entity.WebhookSubscription {
+	return &entity.WebhookSubscription{
+		Name:       r.Name,
+		URL:        r.URL,
+		EventTypes: strings.Join(r.EventTypes, ","),
+	}
+}
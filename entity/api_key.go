@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"strings"
+	"time"
+)
+
+// APIKeyPrefix menandai API key sehingga bisa dibedakan dari JWT dan mudah dikenali jika bocor
+const APIKeyPrefix = "etk_"
+
+type APIKeyScope string
+
+const (
+	EventsWriteScope  APIKeyScope = "events:write"
+	TicketsReadScope  APIKeyScope = "tickets:read"
+	TicketsWriteScope APIKeyScope = "tickets:write"
+	ReportsReadScope  APIKeyScope = "reports:read"
+	// OnBehalfScope mengizinkan partner bertindak sebagai user lain lewat header X-On-Behalf-Of
+	OnBehalfScope APIKeyScope = "users:on_behalf"
+)
+
+var APIKeyScopes = []APIKeyScope{EventsWriteScope, TicketsReadScope, TicketsWriteScope, ReportsReadScope, OnBehalfScope}
+
+type APIKey struct {
+	BaseEntity
+	OrganizationID uuid.UUID  `gorm:"type:char(36);index" json:"-"`
+	UserID         uuid.UUID  `gorm:"type:char(36);index" json:"user_id"`
+	CreatedByID    uuid.UUID  `gorm:"type:char(36)" json:"created_by_id"`
+	Name           string     `json:"name"`
+	Prefix         string     `gorm:"type:varchar(16)" json:"prefix"`
+	KeyHash        string     `gorm:"type:char(64);unique" json:"-"`
+	Scopes         string     `json:"scopes"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	LastUsedIP     string     `json:"last_used_ip"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	User           User       `json:"-" gorm:"foreignKey:UserID"`
+}
+
+func (k *APIKey) IsActive() bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || time.Now().Before(*k.ExpiresAt))
+}
+
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	for _, s := range strings.Split(k.Scopes, ",") {
+		if APIKeyScope(s) == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"time"
+)
+
+// AuditLog hanya boleh ditambah, tidak pernah diubah atau dihapus kecuali untuk menganonimkan user
+type AuditLog struct {
+	BaseEntity
+	OrganizationID uuid.UUID  `gorm:"type:char(36);index" json:"organization_id"`
+	ActorID        *uuid.UUID `gorm:"type:char(36);index" json:"actor_id"`
+	APIKeyID       *uuid.UUID `gorm:"type:char(36)" json:"api_key_id,omitempty"`
+	Action         string     `gorm:"type:varchar(64);index" json:"action"`
+	EntityType     string     `gorm:"type:varchar(64)" json:"entity_type"`
+	EntityID       string     `gorm:"type:varchar(191);index" json:"entity_id"`
+	IP             string     `gorm:"type:varchar(45)" json:"ip"`
+	RequestID      string     `gorm:"type:varchar(64);index" json:"request_id"`
+	Changes        string     `gorm:"type:text" json:"changes,omitempty"`
+	Details        string     `gorm:"type:text" json:"details,omitempty"`
+	CreatedAt      time.Time  `gorm:"index" json:"created_at"`
+}
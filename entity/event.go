@@ -15,18 +15,23 @@ const (
 
 type Event struct {
 	BaseEntity
-	Name        string      `gorm:"unique" json:"name"`
-	Description string      `json:"description"`
-	StartDate   time.Time   `json:"start_date"`
-	EndDate     time.Time   `json:"end_date"`
-	Capacity    int         `json:"capacity"`
-	Price       float64     `json:"price"`
-	Status      EventStatus `json:"status" gorm:"type:ENUM('active', 'ongoing', 'completed');default:'active'"`
-	Location    string      `json:"location"`
-	Tickets     []Ticket    `json:"-" gorm:"foreignKey:EventID"`
-	CreatedBy   uuid.UUID   `gorm:"type:char(36)" json:"created_by"`
-	TicketsSold int         `json:"tickets_sold" gorm:"-"`
-	User        User        `json:"-" gorm:"foreignKey:CreatedBy;references:ID"`
+	OrganizationID    uuid.UUID    `gorm:"type:char(36);uniqueIndex:idx_events_organization_name" json:"organization_id"`
+	Name              string       `gorm:"uniqueIndex:idx_events_organization_name" json:"name"`
+	Description       string       `json:"description"`
+	StartDate         time.Time    `json:"start_date"`
+	EndDate           time.Time    `json:"end_date"`
+	Capacity          int          `json:"capacity"`
+	Price             float64      `json:"price"`
+	Status            EventStatus  `json:"status" gorm:"type:ENUM('active', 'ongoing', 'completed');default:'active'"`
+	Location          string       `json:"location"`
+	MaxTicketsPerUser int          `json:"max_tickets_per_user" gorm:"default:0"`
+	QueueEnabled      bool         `json:"queue_enabled" gorm:"default:false"`
+	QueueAdmitRate    int          `json:"queue_admit_rate" gorm:"default:0"`
+	Tickets           []Ticket     `json:"-" gorm:"foreignKey:EventID"`
+	CreatedBy         uuid.UUID    `gorm:"type:char(36)" json:"created_by"`
+	TicketsSold       int          `json:"tickets_sold" gorm:"not null;default:0"`
+	User              User         `json:"-" gorm:"foreignKey:CreatedBy;references:ID"`
+	Organization      Organization `json:"-" gorm:"foreignKey:OrganizationID"`
 }
 
 func (e *Event) CanBeModified() bool {
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"time"
+)
+
+type LoginThrottle struct {
+	BaseEntity
+	OrganizationID uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_login_throttles_key"`
+	ThrottleKey    string    `gorm:"type:varchar(191);uniqueIndex:idx_login_throttles_key"`
+	Failures       int       `gorm:"default:0"`
+	LastFailedAt   time.Time
+	LockedUntil    *time.Time
+}
+
+func (t *LoginThrottle) IsLocked() bool {
+	return t.LockedUntil != nil && time.Now().Before(*t.LockedUntil)
+}
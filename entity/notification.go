@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"time"
+)
+
+type NotificationType string
+
+const (
+	TicketPurchasedNotification NotificationType = "ticket_purchased"
+	TicketCancelledNotification NotificationType = "ticket_cancelled"
+	TicketRefundedNotification  NotificationType = "ticket_refunded"
+	EventReminderNotification   NotificationType = "event_reminder"
+	EventChangedNotification    NotificationType = "event_changed"
+)
+
+type NotificationStatus string
+
+const (
+	PendingNotification NotificationStatus = "pending"
+	SentNotification    NotificationStatus = "sent"
+	FailedNotification  NotificationStatus = "failed"
+)
+
+// Notification adalah satu email notifikasi ke seorang user. Baris dibuat saat notifikasi
+// dipicu dan menyimpan data template di Payload; worker mengirimnya saat NextAttemptAt
+// tercapai, lalu mengisi penerima dan subject. Baris yang sama menjadi log pengiriman.
+type Notification struct {
+	BaseEntity
+	OrganizationID uuid.UUID          `gorm:"type:char(36);index" json:"-"`
+	UserID         uuid.UUID          `gorm:"type:char(36);index" json:"user_id"`
+	Type           NotificationType   `gorm:"type:varchar(32)" json:"type"`
+	Language       Language           `gorm:"type:varchar(5)" json:"language"`
+	Recipient      string             `json:"recipient"`
+	Subject        string             `json:"subject"`
+	Status         NotificationStatus `gorm:"type:varchar(16);index" json:"status"`
+	Attempts       int                `json:"attempts"`
+	LastError      string             `gorm:"type:text" json:"last_error,omitempty"`
+	Payload        string             `gorm:"type:mediumtext" json:"-"`
+	NextAttemptAt  *time.Time         `gorm:"index" json:"-"`
+	SentAt         *time.Time         `json:"sent_at"`
+}
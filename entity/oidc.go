@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"time"
+)
+
+// OIDCState menyimpan state, nonce, dan code verifier PKCE selama alur login OIDC berlangsung
+type OIDCState struct {
+	BaseEntity
+	OrganizationID uuid.UUID `gorm:"type:char(36);index"`
+	Provider       string    `gorm:"type:varchar(64)"`
+	StateHash      string    `gorm:"type:char(64);unique"`
+	Nonce          string
+	CodeVerifier   string
+	ExpiresAt      time.Time `gorm:"index"`
+}
+
+// UserIdentity menghubungkan akun di identity provider dengan user
+type UserIdentity struct {
+	BaseEntity
+	OrganizationID uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_user_identities_subject" json:"-"`
+	UserID         uuid.UUID `gorm:"type:char(36);index" json:"-"`
+	Provider       string    `gorm:"type:varchar(64);uniqueIndex:idx_user_identities_subject" json:"provider"`
+	Subject        string    `gorm:"type:varchar(255);uniqueIndex:idx_user_identities_subject" json:"subject"`
+	Email          string    `json:"email"`
+	User           User      `json:"-" gorm:"foreignKey:UserID"`
+}
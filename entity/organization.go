@@ -0,0 +1,8 @@
+package entity
+
+type Organization struct {
+	BaseEntity
+	Name   string  `gorm:"unique" json:"name"`
+	Users  []User  `json:"-" gorm:"foreignKey:OrganizationID"`
+	Events []Event `json:"-" gorm:"foreignKey:OrganizationID"`
+}
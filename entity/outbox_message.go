@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"time"
+)
+
+// OutboxMessage adalah domain event yang menunggu dikirim oleh relay. Baris ditulis dalam
+// transaksi yang sama dengan perubahan datanya sehingga hanya perubahan yang sudah commit
+// yang memicu efek samping.
+type OutboxMessage struct {
+	BaseEntity
+	OrganizationID uuid.UUID  `gorm:"type:char(36);index" json:"organization_id"`
+	Type           string     `gorm:"type:varchar(64)" json:"type"`
+	Payload        string     `gorm:"type:mediumtext" json:"payload"`
+	AvailableAt    time.Time  `gorm:"index:idx_outbox_pending" json:"available_at"`
+	PublishedAt    *time.Time `gorm:"index:idx_outbox_pending" json:"published_at"`
+	Attempts       int        `json:"attempts"`
+	LastError      string     `gorm:"type:text" json:"last_error,omitempty"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
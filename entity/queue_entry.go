@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"time"
+)
+
+type QueueEntryStatus string
+
+const (
+	QueueWaiting  QueueEntryStatus = "waiting"
+	QueueAdmitted QueueEntryStatus = "admitted"
+	QueueUsed     QueueEntryStatus = "used"
+	QueueExpired  QueueEntryStatus = "expired"
+)
+
+// QueueEntry adalah posisi seorang user di ruang tunggu sebuah event.
+// Urutan antrean mengikuti ID (UUIDv7) sehingga tidak perlu kolom nomor urut.
+type QueueEntry struct {
+	BaseEntity
+	OrganizationID uuid.UUID        `gorm:"type:char(36);index" json:"-"`
+	EventID        uuid.UUID        `gorm:"type:char(36);index:idx_queue_entries_event_status" json:"event_id"`
+	UserID         uuid.UUID        `gorm:"type:char(36);index" json:"-"`
+	TokenHash      string           `gorm:"type:char(64);unique" json:"-"`
+	Status         QueueEntryStatus `gorm:"type:varchar(16);index:idx_queue_entries_event_status" json:"status"`
+	AdmittedAt     *time.Time       `json:"admitted_at"`
+	ExpiresAt      *time.Time       `json:"expires_at"`
+}
+
+func (q *QueueEntry) IsAdmitted() bool {
+	return q.Status == QueueAdmitted && q.ExpiresAt != nil && time.Now().Before(*q.ExpiresAt)
+}
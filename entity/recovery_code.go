@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"time"
+)
+
+type RecoveryCode struct {
+	BaseEntity
+	OrganizationID uuid.UUID  `gorm:"type:char(36);index" json:"-"`
+	UserID         uuid.UUID  `gorm:"type:char(36);index" json:"-"`
+	CodeHash       string     `gorm:"type:char(64)" json:"-"`
+	UsedAt         *time.Time `json:"-"`
+}
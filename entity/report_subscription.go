@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"strings"
+	"time"
+)
+
+type ReportType string
+
+const (
+	SummaryReportType ReportType = "summary"
+	EventReportType   ReportType = "event"
+	SalesReportType   ReportType = "sales"
+)
+
+type ReportFormat string
+
+const (
+	CSVReportFormat  ReportFormat = "csv"
+	HTMLReportFormat ReportFormat = "html"
+)
+
+// ReportSubscription adalah laporan yang dikirim berkala lewat email. Schedule memakai
+// ekspresi cron lima field yang dihitung menurut Timezone.
+type ReportSubscription struct {
+	BaseEntity
+	OrganizationID uuid.UUID    `gorm:"type:char(36);index" json:"-"`
+	CreatedByID    uuid.UUID    `gorm:"type:char(36)" json:"created_by_id"`
+	Name           string       `json:"name"`
+	ReportType     ReportType   `gorm:"type:varchar(16)" json:"report_type"`
+	EventID        *uuid.UUID   `gorm:"type:char(36);index" json:"event_id"`
+	Schedule       string       `gorm:"type:varchar(100)" json:"schedule"`
+	Timezone       string       `gorm:"type:varchar(64)" json:"timezone"`
+	Recipients     string       `gorm:"type:text" json:"recipients"`
+	Format         ReportFormat `gorm:"type:varchar(8)" json:"format"`
+	NextRunAt      time.Time    `gorm:"index" json:"next_run_at"`
+	LastRunAt      *time.Time   `json:"last_run_at"`
+	LastError      string       `gorm:"type:text" json:"last_error"`
+}
+
+func (s *ReportSubscription) RecipientList() []string {
+	var recipients []string
+	for _, recipient := range strings.Split(s.Recipients, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+type SigningKey struct {
+	BaseEntity
+	Algorithm  string     `gorm:"type:varchar(16)"`
+	PrivateKey string     `gorm:"type:text"`
+	PublicKey  string     `gorm:"type:text"`
+	RetiredAt  *time.Time `gorm:"index"`
+	ExpiresAt  *time.Time `gorm:"index"`
+}
+
+func (k *SigningKey) IsRetired() bool {
+	return k.RetiredAt != nil
+}
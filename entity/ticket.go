@@ -15,14 +15,19 @@ const (
 
 type Ticket struct {
 	BaseEntity
-	EventID      uuid.UUID    `json:"event_id" binding:"required"`
-	UserID       uuid.UUID    `json:"user_id"`
-	PurchaseDate time.Time    `json:"purchase_date"`
-	Status       TicketStatus `json:"status" gorm:"type:ENUM('available', 'purchased', 'cancelled');default:'available'"`
-	BookingCode  string       `json:"booking_code" gorm:"unique"`
-	Price        float64      `json:"price"`
-	Event        Event        `json:"event" gorm:"foreignKey:EventID"`
-	User         User         `json:"-" gorm:"foreignKey:UserID"`
+	OrganizationID uuid.UUID    `gorm:"type:char(36);index" json:"-"`
+	EventID        uuid.UUID    `json:"event_id" binding:"required"`
+	UserID         uuid.UUID    `json:"user_id"`
+	PurchaseDate   time.Time    `json:"purchase_date"`
+	Status         TicketStatus `json:"status" gorm:"type:ENUM('available', 'purchased', 'cancelled');default:'available'"`
+	BookingCode    string       `json:"booking_code" gorm:"unique"`
+	Price          float64      `json:"price"`
+	CancelledAt    *time.Time   `json:"cancelled_at"`
+	CheckedInAt    *time.Time   `json:"checked_in_at"`
+	CheckInGate    string       `json:"check_in_gate" gorm:"type:varchar(50)"`
+	QueueToken     string       `json:"-" gorm:"-"`
+	Event          Event        `json:"event" gorm:"foreignKey:EventID"`
+	User           User         `json:"-" gorm:"foreignKey:UserID"`
 }
 
 func (t *Ticket) CanBeCancelled() bool {
@@ -0,0 +1,14 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+)
+
+// TicketReminder menandai pengingat event yang sudah dikirim untuk satu tiket pada satu
+// offset, sehingga pengingat yang sama tidak dikirim ulang setelah aplikasi restart
+type TicketReminder struct {
+	BaseEntity
+	OrganizationID uuid.UUID `gorm:"type:char(36);index" json:"-"`
+	TicketID       uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_ticket_reminders_ticket_offset" json:"ticket_id"`
+	OffsetMinutes  int       `gorm:"uniqueIndex:idx_ticket_reminders_ticket_offset" json:"offset_minutes"`
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"time"
+)
+
+type RefreshToken struct {
+	BaseEntity
+	OrganizationID  uuid.UUID  `gorm:"type:char(36);index" json:"-"`
+	UserID          uuid.UUID  `gorm:"type:char(36);index" json:"-"`
+	SessionID       uuid.UUID  `gorm:"type:char(36);index" json:"-"`
+	TokenHash       string     `gorm:"type:char(64);unique" json:"-"`
+	AccessTokenID   string     `gorm:"type:char(36);index" json:"-"`
+	AccessExpiresAt time.Time  `json:"-"`
+	ExpiresAt       time.Time  `json:"-"`
+	RevokedAt       *time.Time `json:"-"`
+	MFA             bool       `json:"-"`
+	User            User       `json:"-" gorm:"foreignKey:UserID"`
+}
+
+func (t *RefreshToken) IsActive() bool {
+	return t.RevokedAt == nil && time.Now().Before(t.ExpiresAt)
+}
+
+// RevokedToken menyimpan jti access token yang sudah dicabut sampai masa berlakunya habis
+type RevokedToken struct {
+	TokenID   string    `gorm:"type:char(36);primary_key"`
+	ExpiresAt time.Time `gorm:"index"`
+}
@@ -1,5 +1,10 @@
 package entity
 
+import (
+	"github.com/gofrs/uuid/v5"
+	"time"
+)
+
 type Role string
 
 const (
@@ -7,11 +12,58 @@ const (
 	UserRole  Role = "user"
 )
 
+// Language adalah bahasa yang dipakai untuk email notifikasi ke user
+type Language string
+
+const (
+	IndonesianLanguage Language = "id"
+	EnglishLanguage    Language = "en"
+)
+
+// AnonymizedEmailDomain dipakai sebagai pengganti email user yang sudah dihapus
+const AnonymizedEmailDomain = "@anonymized.invalid"
+
 type User struct {
 	BaseEntity
-	Name     string   `json:"name" binding:"required"`
-	Email    string   `gorm:"unique" json:"email" binding:"required,email"`
-	Password string   `json:"password,omitempty" binding:"required,min=6"`
-	Role     Role     `json:"role" gorm:"type:ENUM('admin', 'user');default:'user'"`
-	Tickets  []Ticket `json:"-" gorm:"foreignKey:UserID"`
+	OrganizationID       uuid.UUID    `gorm:"type:char(36);uniqueIndex:idx_users_organization_email" json:"organization_id"`
+	Name                 string       `json:"name" binding:"required"`
+	Email                string       `gorm:"uniqueIndex:idx_users_organization_email" json:"email" binding:"required,email"`
+	Password             string       `json:"password,omitempty" binding:"required,min=6"`
+	Role                 Role         `json:"role" gorm:"type:ENUM('admin', 'user');default:'user'"`
+	EmailVerifiedAt      *time.Time   `json:"email_verified_at"`
+	PendingEmail         string       `json:"pending_email,omitempty"`
+	TwoFactorEnabledAt   *time.Time   `json:"two_factor_enabled_at"`
+	TOTPSecret           string       `json:"-"`
+	TOTPLastStep         int64        `json:"-"`
+	SuspendedAt          *time.Time   `json:"suspended_at"`
+	Language             Language     `json:"language" gorm:"type:varchar(5);default:'id'"`
+	EventRemindersOptOut bool         `json:"event_reminders_opt_out" gorm:"default:false"`
+	Tickets              []Ticket     `json:"-" gorm:"foreignKey:UserID"`
+	Organization         Organization `json:"-" gorm:"foreignKey:OrganizationID"`
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsTwoFactorEnabled() bool {
+	return u.TwoFactorEnabledAt != nil
+}
+
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
+}
+
+// AnonymizedUserColumns berisi nilai pengganti untuk semua kolom PII milik user
+func AnonymizedUserColumns(id string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                  "Deleted User",
+		"email":                 "deleted-" + id + AnonymizedEmailDomain,
+		"password":              "",
+		"pending_email":         "",
+		"email_verified_at":     nil,
+		"two_factor_enabled_at": nil,
+		"totp_secret":           "",
+		"totp_last_step":        0,
+	}
 }
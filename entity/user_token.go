@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"time"
+)
+
+type UserTokenPurpose string
+
+const (
+	PasswordResetToken     UserTokenPurpose = "password_reset"
+	EmailVerificationToken UserTokenPurpose = "email_verification"
+	TwoFactorChallenge     UserTokenPurpose = "two_factor_challenge"
+	EmailChangeToken       UserTokenPurpose = "email_change"
+)
+
+type UserToken struct {
+	BaseEntity
+	OrganizationID uuid.UUID        `gorm:"type:char(36);index" json:"-"`
+	UserID         uuid.UUID        `gorm:"type:char(36);index" json:"-"`
+	Purpose        UserTokenPurpose `gorm:"type:varchar(32)" json:"-"`
+	TokenHash      string           `gorm:"type:char(64);unique" json:"-"`
+	ExpiresAt      time.Time        `json:"-"`
+	UsedAt         *time.Time       `json:"-"`
+	User           User             `json:"-" gorm:"foreignKey:UserID"`
+}
+
+func (t *UserToken) IsValid() bool {
+	return t.UsedAt == nil && time.Now().Before(t.ExpiresAt)
+}
@@ -0,0 +1,78 @@
+package entity
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"strings"
+	"time"
+)
+
+// WebhookSecretPrefix menandai secret webhook yang dibuat oleh sistem
+const WebhookSecretPrefix = "whsec_"
+
+// WebhookEventType adalah jenis perubahan yang dikirim ke webhook partner
+type WebhookEventType string
+
+const (
+	TicketPurchasedWebhook WebhookEventType = "ticket.purchased"
+	TicketCancelledWebhook WebhookEventType = "ticket.cancelled"
+	TicketCheckedInWebhook WebhookEventType = "ticket.checked_in"
+	EventCreatedWebhook    WebhookEventType = "event.created"
+	EventUpdatedWebhook    WebhookEventType = "event.updated"
+	EventDeletedWebhook    WebhookEventType = "event.deleted"
+)
+
+var WebhookEventTypes = []WebhookEventType{
+	TicketPurchasedWebhook, TicketCancelledWebhook, TicketCheckedInWebhook,
+	EventCreatedWebhook, EventUpdatedWebhook, EventDeletedWebhook,
+}
+
+// WebhookSubscription adalah URL partner yang menerima jenis perubahan tertentu.
+// Secret disimpan terenkripsi karena dibutuhkan untuk menandatangani setiap pengiriman.
+type WebhookSubscription struct {
+	BaseEntity
+	OrganizationID uuid.UUID `gorm:"type:char(36);index" json:"-"`
+	CreatedByID    uuid.UUID `gorm:"type:char(36)" json:"created_by_id"`
+	Name           string    `json:"name"`
+	URL            string    `gorm:"type:varchar(2048)" json:"url"`
+	Secret         string    `gorm:"type:text" json:"-"`
+	EventTypes     string    `json:"event_types"`
+}
+
+func (s *WebhookSubscription) EventTypeList() []string {
+	var eventTypes []string
+	for _, eventType := range strings.Split(s.EventTypes, ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	return eventTypes
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	PendingWebhookDelivery   WebhookDeliveryStatus = "pending"
+	DeliveredWebhookDelivery WebhookDeliveryStatus = "delivered"
+	// DeadWebhookDelivery adalah dead-letter: semua percobaan gagal dan hanya bisa dikirim ulang lewat replay
+	DeadWebhookDelivery WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery adalah satu pengiriman payload ke sebuah WebhookSubscription
+// beserta hasil percobaan terakhirnya
+type WebhookDelivery struct {
+	BaseEntity
+	OrganizationID uuid.UUID             `gorm:"type:char(36);index" json:"-"`
+	SubscriptionID uuid.UUID             `gorm:"type:char(36);uniqueIndex:idx_webhook_deliveries_subscription_message" json:"subscription_id"`
+	MessageID      uuid.UUID             `gorm:"type:char(36);uniqueIndex:idx_webhook_deliveries_subscription_message" json:"message_id"`
+	EventType      WebhookEventType      `gorm:"type:varchar(64)" json:"event_type"`
+	Payload        string                `gorm:"type:mediumtext" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(16);index:idx_webhook_deliveries_due" json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `gorm:"index:idx_webhook_deliveries_due" json:"next_attempt_at"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at"`
+	ResponseStatus int                   `json:"response_status"`
+	ResponseBody   string                `gorm:"type:text" json:"response_body"`
+	LastError      string                `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	Subscription   WebhookSubscription   `json:"-" gorm:"foreignKey:SubscriptionID"`
+}
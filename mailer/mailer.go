@@ -0,0 +1,179 @@
+package mailer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"event-ticketing/config"
+	"event-ticketing/utils"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Message struct {
+	To          []string
+	Subject     string
+	Body        string
+	HTMLBody    string
+	Attachments []Attachment
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+type Mailer interface {
+	Send(message Message) error
+}
+
+// New memilih mailer dari konfigurasi: MAIL_CAPTURE_DIR menyimpan email sebagai file .eml
+// untuk pengujian lokal, SMTP_HOST mengirim lewat SMTP, selain itu email hanya ditulis ke log
+func New(config config.Config) Mailer {
+	if config.MailCaptureDir != "" {
+		return NewFileMailer(config.MailCaptureDir, config.MailFrom)
+	}
+	if config.SMTPHost == "" {
+		return NewLogMailer()
+	}
+	return NewSMTPMailer(config)
+}
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(config config.Config) Mailer {
+	var auth smtp.Auth
+	if config.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", config.SMTPUsername, config.SMTPPassword, config.SMTPHost)
+	}
+
+	return &smtpMailer{
+		addr: config.SMTPHost + ":" + config.SMTPPort,
+		auth: auth,
+		from: config.MailFrom,
+	}
+}
+
+func (m *smtpMailer) Send(message Message) error {
+	return smtp.SendMail(m.addr, m.auth, m.from, message.To, buildMIME(m.from, message))
+}
+
+type logMailer struct{}
+
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(message Message) error {
+	var attachments []string
+	for _, attachment := range message.Attachments {
+		attachments = append(attachments, attachment.Filename)
+	}
+
+	utils.Log.Infof("Email to %s: %s\n%s\nAttachments: %s", strings.Join(message.To, ", "), message.Subject, message.Body, strings.Join(attachments, ", "))
+	return nil
+}
+
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer menyimpan setiap email sebagai file .eml di dir. File bisa dibuka dengan
+// klien email biasa sehingga isi, HTML, dan lampiran bisa diperiksa tanpa server SMTP.
+func NewFileMailer(dir, from string) Mailer {
+	return &fileMailer{dir: dir, from: from}
+}
+
+func (m *fileMailer) Send(message Message) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+	return os.WriteFile(filepath.Join(m.dir, name), buildMIME(m.from, message), 0o644)
+}
+
+// buildMIME menyusun email. Teks saja dikirim sebagai text/plain, HTML menjadi
+// multipart/alternative, dan lampiran membungkus semuanya dalam multipart/mixed.
+func buildMIME(from string, message Message) []byte {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(message.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+
+	if message.HTMLBody == "" && len(message.Attachments) == 0 {
+		msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+		msg.WriteString(message.Body)
+		return msg.Bytes()
+	}
+
+	mixed := mimeBoundary()
+	if len(message.Attachments) > 0 {
+		fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mixed)
+		fmt.Fprintf(&msg, "--%s\r\n", mixed)
+	}
+
+	if message.HTMLBody != "" {
+		alternative := mimeBoundary()
+		fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", alternative)
+		fmt.Fprintf(&msg, "--%s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n", alternative, message.Body)
+		fmt.Fprintf(&msg, "--%s\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%s\r\n", alternative, message.HTMLBody)
+		fmt.Fprintf(&msg, "--%s--\r\n", alternative)
+	} else {
+		fmt.Fprintf(&msg, "Content-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n", message.Body)
+	}
+
+	for _, attachment := range message.Attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		fmt.Fprintf(&msg, "--%s\r\n", mixed)
+		fmt.Fprintf(&msg, "Content-Type: %s\r\n", contentType)
+		msg.WriteString("Content-Transfer-Encoding: base64\r\n")
+		fmt.Fprintf(&msg, "Content-Disposition: attachment; filename=%q\r\n\r\n", attachment.Filename)
+		writeBase64Lines(&msg, attachment.Data)
+	}
+
+	if len(message.Attachments) > 0 {
+		fmt.Fprintf(&msg, "--%s--\r\n", mixed)
+	}
+
+	return msg.Bytes()
+}
+
+// writeBase64Lines memecah base64 per 76 karakter sesuai batas panjang baris MIME
+func writeBase64Lines(buf *bytes.Buffer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+}
+
+func mimeBoundary() string {
+	buf := make([]byte, 12)
+	rand.Read(buf)
+	return "boundary-" + hex.EncodeToString(buf)
+}
@@ -27,6 +27,10 @@ import (
 // @in header
 // @name Authorization
 // @type apiKey
+// @securityDefinitions.apikey PartnerKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key issued by an admin. Access is limited to the key's scopes, keys stop working while their owner is suspended, and when admin two-factor is required a key only reaches admin endpoints if its owner has two-factor enabled. Do not send it together with a bearer token.
 func main() {
 	// Load konfigurasi
 	err := godotenv.Load()
@@ -39,6 +43,14 @@ func main() {
 	// Inisialisasi logger
 	utils.InitLogger(cfg.Environment)
 
+	// Identity provider tiruan untuk pengembangan tidak membutuhkan database
+	if len(os.Args) > 1 && os.Args[1] == "mock-oidc" {
+		if err := mockOIDC(os.Args[2:]); err != nil {
+			log.Fatalf("Command failed: %v", err)
+		}
+		return
+	}
+
 	// Inisialisasi database
 	db, err := config.SetupDatabase(cfg)
 	if err != nil {
@@ -50,6 +62,14 @@ func main() {
 		log.Fatalf("Failed to migrate database: %v", err)
 	}
 
+	// Jalankan perintah administrasi jika ada argumen
+	if len(os.Args) > 1 {
+		if err := runCommand(db, os.Args[1:]); err != nil {
+			log.Fatalf("Command failed: %v", err)
+		}
+		return
+	}
+
 	// Setup routes
 	r := routes.SetupRoutes(db, cfg)
 	srv := &http.Server{
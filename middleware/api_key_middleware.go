@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	APIKeyHeader   = "X-API-Key"
+	OnBehalfHeader = "X-On-Behalf-Of"
+)
+
+var errAmbiguousCredentials = errors.New("send either an API key or a bearer token, not both")
+
+// APIKeyMiddleware menerima API key partner selain Bearer JWT biasa. Request dengan
+// JWT diteruskan ke AuthMiddleware, sedangkan API key harus memiliki scope yang diminta.
+func APIKeyMiddleware(userRepo repository.UserRepository, tokenRepo repository.TokenRepository, apiKeyRepo repository.APIKeyRepository, keyStore *KeyStore, scope entity.APIKeyScope) gin.HandlerFunc {
+	authenticateJWT := AuthMiddleware(userRepo, tokenRepo, keyStore)
+
+	return func(c *gin.Context) {
+		tokenString, err := extractToken(c)
+		if err != nil {
+			utils.UnauthorizedResponse(c, "Unauthorized: "+err.Error())
+			c.Abort()
+			return
+		}
+		if !strings.HasPrefix(tokenString, entity.APIKeyPrefix) {
+			authenticateJWT(c)
+			return
+		}
+
+		key, err := apiKeyRepo.FindByHash(utils.HashToken(tokenString))
+		if err != nil || !key.IsActive() {
+			utils.UnauthorizedResponse(c, "Unauthorized: Invalid API key")
+			c.Abort()
+			return
+		}
+
+		if !key.HasScope(scope) {
+			utils.ForbiddenResponse(c, "Forbidden: API key is missing scope "+string(scope))
+			c.Abort()
+			return
+		}
+
+		organizationID := key.OrganizationID.String()
+		users := userRepo.WithTenant(organizationID)
+
+		// Pemilik key selalu diperiksa, juga saat bertindak atas nama user lain, sehingga
+		// key milik user yang di-suspend langsung berhenti bekerja
+		owner, err := users.FindByID(key.UserID.String())
+		if err != nil {
+			utils.UnauthorizedResponse(c, "Unauthorized: User not found")
+			c.Abort()
+			return
+		}
+
+		if owner.IsSuspended() {
+			utils.UnauthorizedResponse(c, "Unauthorized: Account suspended")
+			c.Abort()
+			return
+		}
+
+		user := owner
+
+		// Partner dapat membuat tiket atas nama user tanpa mengetahui password user tersebut
+		if onBehalfOf := c.GetHeader(OnBehalfHeader); onBehalfOf != "" && onBehalfOf != owner.ID.String() {
+			if !key.HasScope(entity.OnBehalfScope) {
+				utils.ForbiddenResponse(c, "Forbidden: API key is missing scope "+string(entity.OnBehalfScope))
+				c.Abort()
+				return
+			}
+
+			user, err = users.FindByID(onBehalfOf)
+			if err != nil {
+				utils.UnauthorizedResponse(c, "Unauthorized: User not found")
+				c.Abort()
+				return
+			}
+
+			// Bertindak atas nama admin lain akan memberi partner hak yang tidak diberikan ke key ini
+			if user.Role == entity.AdminRole {
+				utils.ForbiddenResponse(c, "Forbidden: Cannot act on behalf of an admin")
+				c.Abort()
+				return
+			}
+
+			if user.IsSuspended() {
+				utils.UnauthorizedResponse(c, "Unauthorized: Account suspended")
+				c.Abort()
+				return
+			}
+		}
+
+		if err := apiKeyRepo.TouchLastUsed(key.ID.String(), c.ClientIP()); err != nil {
+			utils.Log.Errorf("Failed to update API key last used: %v", err)
+		}
+
+		c.Set("user", user)
+		c.Set("userID", user.ID.String())
+		c.Set("organizationID", organizationID)
+		c.Set("apiKeyID", key.ID.String())
+		c.Set("role", user.Role)
+		c.Set("mfa", false)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"errors"
+	"event-ticketing/config"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofrs/uuid/v5"
+)
+
+const testAPIKey = entity.APIKeyPrefix + "test-key"
+
+type fakeUserRepository struct {
+	repository.UserRepository
+	users map[string]*entity.User
+}
+
+func (r *fakeUserRepository) WithTenant(organizationID string) repository.UserRepository {
+	return r
+}
+
+func (r *fakeUserRepository) FindByID(id string) (*entity.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	copied := *user
+	return &copied, nil
+}
+
+type fakeAPIKeyRepository struct {
+	repository.APIKeyRepository
+	key *entity.APIKey
+}
+
+func (r *fakeAPIKeyRepository) FindByHash(hash string) (*entity.APIKey, error) {
+	if hash != utils.HashToken(testAPIKey) {
+		return nil, errors.New("api key not found")
+	}
+	return r.key, nil
+}
+
+func (r *fakeAPIKeyRepository) TouchLastUsed(id string, ip string) error {
+	return nil
+}
+
+type apiKeyTestSetup struct {
+	owner  *entity.User
+	target *entity.User
+	users  *fakeUserRepository
+	router *gin.Engine
+}
+
+func newAPIKeyTestSetup(ownerRole entity.Role, cfg config.Config) *apiKeyTestSetup {
+	gin.SetMode(gin.TestMode)
+
+	owner := &entity.User{BaseEntity: entity.BaseEntity{ID: uuid.Must(uuid.NewV7())}, Role: ownerRole}
+	target := &entity.User{BaseEntity: entity.BaseEntity{ID: uuid.Must(uuid.NewV7())}, Role: entity.UserRole}
+	users := &fakeUserRepository{users: map[string]*entity.User{owner.ID.String(): owner, target.ID.String(): target}}
+	keys := &fakeAPIKeyRepository{key: &entity.APIKey{
+		BaseEntity: entity.BaseEntity{ID: uuid.Must(uuid.NewV7())},
+		UserID:     owner.ID,
+		Scopes:     string(entity.ReportsReadScope) + "," + string(entity.OnBehalfScope),
+	}}
+
+	router := gin.New()
+	router.GET("/reports", APIKeyMiddleware(users, nil, keys, nil, entity.ReportsReadScope), AdminMiddleware(cfg), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("userID"))
+	})
+	router.GET("/tickets", APIKeyMiddleware(users, nil, keys, nil, entity.ReportsReadScope), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("userID"))
+	})
+
+	return &apiKeyTestSetup{owner: owner, target: target, users: users, router: router}
+}
+
+func (s *apiKeyTestSetup) do(path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set(APIKeyHeader, testAPIKey)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIKeyMiddlewareRejectsSuspendedOwnerActingOnBehalf(t *testing.T) {
+	setup := newAPIKeyTestSetup(entity.UserRole, config.Config{})
+
+	w := setup.do("/tickets", map[string]string{OnBehalfHeader: setup.target.ID.String()})
+	if w.Code != http.StatusOK || w.Body.String() != setup.target.ID.String() {
+		t.Fatalf("expected request on behalf of target to succeed, got %d %s", w.Code, w.Body.String())
+	}
+
+	suspendedAt := time.Now()
+	setup.users.users[setup.owner.ID.String()].SuspendedAt = &suspendedAt
+
+	w = setup.do("/tickets", map[string]string{OnBehalfHeader: setup.target.ID.String()})
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected key of suspended owner to be rejected, got %d", w.Code)
+	}
+}
+
+func TestAPIKeyMiddlewareRejectsAmbiguousCredentials(t *testing.T) {
+	setup := newAPIKeyTestSetup(entity.AdminRole, config.Config{})
+
+	w := setup.do("/reports", map[string]string{"Authorization": "Bearer some.jwt.token"})
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected request with both API key and bearer token to be rejected, got %d", w.Code)
+	}
+}
+
+func TestAdminMiddlewareRequiresTwoFactorForAdminAPIKeys(t *testing.T) {
+	setup := newAPIKeyTestSetup(entity.AdminRole, config.Config{RequireAdminTwoFactor: true})
+
+	if w := setup.do("/reports", nil); w.Code != http.StatusForbidden {
+		t.Fatalf("expected key of admin without 2FA to be rejected, got %d", w.Code)
+	}
+
+	enabledAt := time.Now()
+	setup.users.users[setup.owner.ID.String()].TwoFactorEnabledAt = &enabledAt
+
+	if w := setup.do("/reports", nil); w.Code != http.StatusOK {
+		t.Fatalf("expected key of admin with 2FA to be accepted, got %d", w.Code)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"event-ticketing/entity"
 	"event-ticketing/repository"
 	"event-ticketing/utils"
-	"fmt"
 	"strings"
 	"time"
 
@@ -15,44 +14,67 @@ import (
 )
 
 type Claims struct {
-	UserID string      `json:"user_id"`
-	Email  string      `json:"email"`
-	Role   entity.Role `json:"role"`
+	UserID         string      `json:"user_id"`
+	OrganizationID string      `json:"organization_id"`
+	Email          string      `json:"email"`
+	Role           entity.Role `json:"role"`
+	MFA            bool        `json:"mfa,omitempty"`
 	jwt.StandardClaims
 }
 
-func AuthMiddleware(userRepo repository.UserRepository, config config.Config) gin.HandlerFunc {
+func AuthMiddleware(userRepo repository.UserRepository, tokenRepo repository.TokenRepository, keyStore *KeyStore) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenString := extractToken(c)
+		tokenString, err := extractToken(c)
+		if err != nil {
+			utils.UnauthorizedResponse(c, "Unauthorized: "+err.Error())
+			c.Abort()
+			return
+		}
 		if tokenString == "" {
 			utils.UnauthorizedResponse(c, "Unauthorized: Token not provided")
 			c.Abort()
 			return
 		}
 
-		claims, err := validateToken(tokenString, config.JWTSecret)
+		claims, err := validateToken(tokenString, keyStore)
 		if err != nil {
 			utils.UnauthorizedResponse(c, "Unauthorized: "+err.Error())
 			c.Abort()
 			return
 		}
 
-		user, err := userRepo.FindByID(claims.UserID)
+		revoked, err := tokenRepo.IsAccessTokenRevoked(claims.Id)
+		if err != nil || revoked {
+			utils.UnauthorizedResponse(c, "Unauthorized: Token has been revoked")
+			c.Abort()
+			return
+		}
+
+		user, err := userRepo.WithTenant(claims.OrganizationID).FindByID(claims.UserID)
 		if err != nil {
 			utils.UnauthorizedResponse(c, "Unauthorized: User not found")
 			c.Abort()
 			return
 		}
 
+		if user.IsSuspended() {
+			utils.UnauthorizedResponse(c, "Unauthorized: Account suspended")
+			c.Abort()
+			return
+		}
+
 		c.Set("user", user)
 		c.Set("userID", claims.UserID)
-		c.Set("role", claims.Role)
+		c.Set("organizationID", claims.OrganizationID)
+		c.Set("tokenID", claims.Id)
+		c.Set("role", user.Role)
+		c.Set("mfa", claims.MFA)
 
 		c.Next()
 	}
 }
 
-func AdminMiddleware() gin.HandlerFunc {
+func AdminMiddleware(config config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
 		if !exists {
@@ -67,23 +89,64 @@ func AdminMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// API key tidak membawa faktor kedua. Saat 2FA admin diwajibkan, API key hanya diterima
+		// jika admin pemiliknya sudah memasang 2FA; aksesnya tetap dibatasi scope key.
+		if config.RequireAdminTwoFactor && !c.GetBool("mfa") {
+			if c.GetString("apiKeyID") == "" || !adminHasTwoFactor(c) {
+				utils.ForbiddenResponse(c, "Forbidden: Two-factor authentication required")
+				c.Abort()
+				return
+			}
+		}
+
 		c.Next()
 	}
 }
 
-func GenerateToken(userID string, email string, role entity.Role, secret string, expiresIn time.Duration) (string, error) {
+// adminHasTwoFactor memeriksa apakah user pada request sudah memasang 2FA
+func adminHasTwoFactor(c *gin.Context) bool {
+	user, ok := c.Get("user")
+	if !ok {
+		return false
+	}
+	admin, ok := user.(*entity.User)
+	return ok && admin.IsTwoFactorEnabled()
+}
+
+// VerifiedEmailMiddleware menolak user yang belum verifikasi email jika diwajibkan oleh konfigurasi
+func VerifiedEmailMiddleware(config config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !config.RequireEmailVerification {
+			c.Next()
+			return
+		}
+
+		user, ok := c.MustGet("user").(*entity.User)
+		if !ok || !user.IsEmailVerified() {
+			utils.ForbiddenResponse(c, "Forbidden: Email verification required")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func GenerateToken(keyStore *KeyStore, tokenID string, userID string, organizationID string, email string, role entity.Role, mfa bool, expiresIn time.Duration) (string, error) {
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
+		UserID:         userID,
+		OrganizationID: organizationID,
+		Email:          email,
+		Role:           role,
+		MFA:            mfa,
 		StandardClaims: jwt.StandardClaims{
+			Id:        tokenID,
 			ExpiresAt: time.Now().Add(expiresIn).Unix(),
 			IssuedAt:  time.Now().Unix(),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(secret))
+	signedToken, err := keyStore.Sign(claims)
 
 	if err != nil {
 		return "", err
@@ -92,29 +155,36 @@ func GenerateToken(userID string, email string, role entity.Role, secret string,
 	return signedToken, nil
 }
 
-func validateToken(tokenString string, secret string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
+func validateToken(tokenString string, keyStore *KeyStore) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, keyStore.Keyfunc)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && claims.Id != "" {
 		return claims, nil
 	}
 
 	return nil, errors.New("invalid token")
 }
 
-func extractToken(c *gin.Context) string {
+// extractToken mengambil API key dari header X-API-Key atau JWT dari header Authorization.
+// Request yang mengirim keduanya ditolak agar jelas kredensial mana yang dipakai untuk
+// otorisasi, misalnya pengecekan 2FA admin.
+func extractToken(c *gin.Context) (string, error) {
+	apiKey := c.GetHeader(APIKeyHeader)
 	bearerToken := c.GetHeader("Authorization")
+
+	if apiKey != "" && bearerToken != "" {
+		return "", errAmbiguousCredentials
+	}
+	if apiKey != "" {
+		return apiKey, nil
+	}
+
 	if len(strings.Split(bearerToken, " ")) == 2 {
-		return strings.Split(bearerToken, " ")[1]
+		return strings.Split(bearerToken, " ")[1], nil
 	}
-	return ""
+	return "", nil
 }
@@ -0,0 +1,390 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"event-ticketing/config"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const keyReloadInterval = time.Minute
+
+// unknownKeyReloadInterval adalah jarak minimum antar reload yang dipicu kid tidak dikenal.
+// Selama jeda ini kid yang tidak dikenal langsung ditolak tanpa membaca database, sehingga
+// token dengan kid acak tidak membebani database.
+const unknownKeyReloadInterval = 10 * time.Second
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	private   crypto.PrivateKey
+	public    crypto.PublicKey
+	retired   bool
+	createdAt time.Time
+}
+
+// KeyStore menyimpan key untuk menandatangani dan memverifikasi JWT.
+// Key dibagikan antar instance lewat database dan dirotasi secara berkala.
+type KeyStore struct {
+	repo   repository.SigningKeyRepository
+	config config.Config
+
+	mu      sync.RWMutex
+	keys    map[string]*signingKey
+	current *signingKey
+	// hmacAcceptUntil adalah batas token HS256 lama masih diterima setelah pindah ke key asimetris
+	hmacAcceptUntil time.Time
+	loadedAt        time.Time
+
+	// reloadMu memastikan hanya satu reload karena kid tidak dikenal yang berjalan sekaligus
+	reloadMu sync.Mutex
+}
+
+func NewKeyStore(repo repository.SigningKeyRepository, config config.Config) *KeyStore {
+	return &KeyStore{
+		repo:   repo,
+		config: config,
+		keys:   map[string]*signingKey{},
+	}
+}
+
+// Start memuat key dari database, membuat key pertama jika belum ada,
+// lalu menjalankan rotasi terjadwal di background
+func (k *KeyStore) Start() error {
+	if err := k.rotateIfDue(); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(keyReloadInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := k.rotateIfDue(); err != nil {
+				utils.Log.Errorf("Failed to rotate signing keys: %v", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (k *KeyStore) Sign(claims jwt.Claims) (string, error) {
+	if k.config.JWTAlgorithm == jwt.SigningMethodHS256.Alg() {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(k.config.JWTSecret))
+	}
+
+	k.mu.RLock()
+	key := k.current
+	k.mu.RUnlock()
+
+	if key == nil {
+		return "", errors.New("no active signing key")
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.private)
+}
+
+// Keyfunc memilih key verifikasi berdasarkan header alg dan kid pada token
+func (k *KeyStore) Keyfunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if !k.acceptsHMAC() {
+			return nil, errors.New("HS256 tokens are no longer accepted")
+		}
+		return []byte(k.config.JWTSecret), nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodEd25519:
+		kid, _ := token.Header["kid"].(string)
+		key, err := k.find(kid)
+		if err != nil {
+			return nil, err
+		}
+		if key.method.Alg() != token.Method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method for key %s: %v", kid, token.Header["alg"])
+		}
+		return key.public, nil
+	default:
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+}
+
+func (k *KeyStore) JWKS() JWKSet {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range k.keys {
+		jwk := JWK{Kid: key.id, Use: "sig", Alg: key.method.Alg()}
+
+		switch public := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
+
+// Rotate membuat key baru sebagai key aktif. Key lama tetap dipublikasikan
+// sampai semua token yang ditandatanganinya kedaluwarsa.
+func (k *KeyStore) Rotate() error {
+	key, err := generateSigningKey(k.config.JWTAlgorithm, k.config.JWTKeySecret)
+	if err != nil {
+		return err
+	}
+
+	if err := k.repo.Create(key); err != nil {
+		return err
+	}
+
+	if err := k.repo.RetireAllExcept(key.ID.String(), time.Now().Add(k.config.JWTExpiresIn+keyReloadInterval)); err != nil {
+		return err
+	}
+
+	utils.Log.Infof("Rotated JWT signing key, new kid %s", key.ID)
+	return k.reload()
+}
+
+func (k *KeyStore) rotateIfDue() error {
+	if err := k.repo.DeleteExpired(); err != nil {
+		return err
+	}
+
+	if err := k.reload(); err != nil {
+		return err
+	}
+
+	if k.config.JWTAlgorithm == jwt.SigningMethodHS256.Alg() {
+		return nil
+	}
+
+	k.mu.RLock()
+	current := k.current
+	k.mu.RUnlock()
+
+	if current != nil && current.method.Alg() == k.config.JWTAlgorithm &&
+		time.Since(current.createdAt) < k.config.JWTKeyRotationInterval {
+		return nil
+	}
+
+	return k.Rotate()
+}
+
+func (k *KeyStore) reload() error {
+	records, err := k.repo.FindValid()
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]*signingKey, len(records))
+	var current *signingKey
+	hmacAcceptUntil := k.config.JWTHS256AcceptUntil
+	if hmacAcceptUntil.IsZero() && len(records) > 0 {
+		// Tanpa JWT_HS256_ACCEPT_UNTIL, token HS256 diterima sampai token terakhir yang terbit
+		// sebelum key pertama dibuat kedaluwarsa. Key lama baru dihapus setelah key penggantinya
+		// berumur lebih dari JWTExpiresIn, jadi rotasi tidak membuka jendela ini lagi.
+		hmacAcceptUntil = records[len(records)-1].CreatedAt.Add(k.config.JWTExpiresIn)
+	}
+	for _, record := range records {
+		key, err := parseSigningKey(record, k.config.JWTKeySecret)
+		if err != nil {
+			utils.Log.Errorf("Failed to load signing key %s: %v", record.ID, err)
+			continue
+		}
+
+		keys[key.id] = key
+		if current == nil && !key.retired {
+			current = key
+		}
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.current = current
+	k.hmacAcceptUntil = hmacAcceptUntil
+	k.loadedAt = time.Now()
+	k.mu.Unlock()
+
+	return nil
+}
+
+func (k *KeyStore) find(kid string) (*signingKey, error) {
+	key, ok, reloadDue := k.lookup(kid)
+	if ok {
+		return key, nil
+	}
+	if !reloadDue {
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+
+	// Key mungkin baru dibuat oleh instance lain. Request lain yang menunggu di sini
+	// memakai hasil reload yang sama dan tidak membaca database lagi.
+	k.reloadMu.Lock()
+	defer k.reloadMu.Unlock()
+
+	if key, ok, reloadDue = k.lookup(kid); ok {
+		return key, nil
+	}
+	if reloadDue {
+		if err := k.reload(); err != nil {
+			return nil, err
+		}
+		if key, ok, _ = k.lookup(kid); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key: %s", kid)
+}
+
+// lookup mencari key berdasarkan kid. Jika tidak ditemukan, reloadDue menandakan
+// apakah reload terakhir sudah cukup lama sehingga boleh memuat ulang dari database.
+func (k *KeyStore) lookup(kid string) (key *signingKey, ok bool, reloadDue bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if key, ok = k.keys[kid]; ok {
+		return key, true, false
+	}
+	return nil, false, time.Since(k.loadedAt) >= unknownKeyReloadInterval
+}
+
+func (k *KeyStore) acceptsHMAC() bool {
+	if k.config.JWTAlgorithm == jwt.SigningMethodHS256.Alg() {
+		return true
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return time.Now().Before(k.hmacAcceptUntil)
+}
+
+func generateSigningKey(algorithm string, secret string) (*entity.SigningKey, error) {
+	var private crypto.PrivateKey
+	var public crypto.PublicKey
+
+	switch algorithm {
+	case jwt.SigningMethodRS256.Alg():
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		private, public = rsaKey, &rsaKey.PublicKey
+	case jwt.SigningMethodEdDSA.Alg():
+		edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		private, public = edPrivate, edPublic
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm: %s", algorithm)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedPrivate, err := utils.Encrypt(secret, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.SigningKey{
+		Algorithm:  algorithm,
+		PrivateKey: encryptedPrivate,
+		PublicKey:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})),
+	}, nil
+}
+
+func parseSigningKey(record entity.SigningKey, secret string) (*signingKey, error) {
+	method := jwt.GetSigningMethod(record.Algorithm)
+	if method == nil {
+		return nil, fmt.Errorf("unsupported JWT algorithm: %s", record.Algorithm)
+	}
+
+	publicBlock, _ := pem.Decode([]byte(record.PublicKey))
+	if publicBlock == nil {
+		return nil, errors.New("invalid public key")
+	}
+
+	public, err := x509.ParsePKIXPublicKey(publicBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{
+		id:        record.ID.String(),
+		method:    method,
+		public:    public,
+		retired:   record.IsRetired(),
+		createdAt: record.CreatedAt,
+	}
+
+	// Key yang sudah pensiun hanya dipakai untuk verifikasi
+	if key.retired {
+		return key, nil
+	}
+
+	privatePEM, err := utils.Decrypt(secret, record.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	privateBlock, _ := pem.Decode(privatePEM)
+	if privateBlock == nil {
+		return nil, errors.New("invalid private key")
+	}
+
+	key.private, err = x509.ParsePKCS8PrivateKey(privateBlock.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const OrganizationHeader = "X-Organization-ID"
+
+// TenantMiddleware menentukan organisasi untuk route publik yang belum memiliki token
+func TenantMiddleware(organizationRepo repository.OrganizationRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		organizationID := c.GetHeader(OrganizationHeader)
+		if organizationID == "" {
+			utils.BadRequestResponse(c, "Organization not provided", OrganizationHeader+" header is required")
+			c.Abort()
+			return
+		}
+
+		organization, err := organizationRepo.FindByID(organizationID)
+		if err != nil {
+			utils.NotFoundResponse(c, "Organization not found")
+			c.Abort()
+			return
+		}
+
+		c.Set("organizationID", organization.ID.String())
+
+		c.Next()
+	}
+}
@@ -0,0 +1,55 @@
+package notification
+
+import (
+	"event-ticketing/entity"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var indonesianDays = []string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"}
+
+var indonesianMonths = []string{"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember"}
+
+// formatDate menampilkan tanggal event dalam zona waktu loc sesuai bahasa user,
+// misalnya "Senin, 2 Februari 2026 pukul 19.00 WIB"
+func formatDate(t time.Time, language entity.Language, loc *time.Location) string {
+	t = t.In(loc)
+	zone, _ := t.Zone()
+
+	if language == entity.EnglishLanguage {
+		return fmt.Sprintf("%s at %s %s", t.Format("Monday, 2 January 2006"), t.Format("15:04"), zone)
+	}
+
+	return fmt.Sprintf("%s, %d %s %d pukul %s %s",
+		indonesianDays[t.Weekday()], t.Day(), indonesianMonths[t.Month()-1], t.Year(), t.Format("15.04"), zone)
+}
+
+// formatMoney memakai pemisah ribuan sesuai bahasa dan menampilkan desimal hanya jika ada
+func formatMoney(value float64, language entity.Language) string {
+	thousands, decimal := ".", ","
+	if language == entity.EnglishLanguage {
+		thousands, decimal = ",", "."
+	}
+
+	cents := int64(math.Round(math.Abs(value) * 100))
+	digits := fmt.Sprint(cents / 100)
+
+	var grouped strings.Builder
+	if value < 0 {
+		grouped.WriteString("-")
+	}
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteString(thousands)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	if fraction := cents % 100; fraction != 0 {
+		fmt.Fprintf(&grouped, "%s%02d", decimal, fraction)
+	}
+	return grouped.String()
+}
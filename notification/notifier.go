@@ -0,0 +1,404 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"event-ticketing/config"
+	"event-ticketing/entity"
+	"event-ticketing/mailer"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+const (
+	// dispatchBatchSize membatasi jumlah notifikasi yang dikirim per putaran
+	dispatchBatchSize = 100
+	workerCount       = 4
+	// dispatchLease adalah waktu sebelum notifikasi yang sedang dikirim boleh diambil worker lain
+	dispatchLease = 5 * time.Minute
+	// maxRetryDelay membatasi jeda percobaan ulang notifikasi yang terus gagal
+	maxRetryDelay = time.Hour
+	// qrScale adalah ukuran piksel per modul QR pada lampiran tiket
+	qrScale = 8
+)
+
+var languages = []entity.Language{entity.IndonesianLanguage, entity.EnglishLanguage}
+
+var notificationTypes = []entity.NotificationType{
+	entity.TicketPurchasedNotification,
+	entity.TicketCancelledNotification,
+	entity.TicketRefundedNotification,
+	entity.EventReminderNotification,
+	entity.EventChangedNotification,
+}
+
+// Notifier mengirim email transaksional ke user. Method hanya menyimpan notifikasi ke
+// tabel notifications; worker membaca baris yang jatuh tempo, menyusun email sesuai bahasa
+// user, mengirimnya, dan mencoba ulang dengan jeda yang makin panjang. Notifikasi tidak
+// hilang saat aplikasi restart karena jadwalnya tersimpan di database.
+//
+// Tiket yang dikirim ke Notifier harus sudah memuat Event.
+type Notifier interface {
+	TicketPurchased(ticket *entity.Ticket) error
+	// TicketCancelled juga mengirim email refund jika tiketnya berbayar
+	TicketCancelled(ticket *entity.Ticket) error
+	EventReminder(ticket *entity.Ticket) error
+	EventChanged(before, after *entity.Event) error
+	EventCancelled(event *entity.Event) error
+	RunDue() error
+	Start()
+	// WithTx mengembalikan Notifier yang menyimpan notifikasi di dalam transaksi tx
+	WithTx(tx *gorm.DB) Notifier
+}
+
+// EventChanges menandai bagian event yang berubah untuk template event_changed
+type EventChanges struct {
+	Before    *entity.Event `json:"before"`
+	Name      bool          `json:"name"`
+	Schedule  bool          `json:"schedule"`
+	Location  bool          `json:"location"`
+	Cancelled bool          `json:"cancelled"`
+}
+
+// templateData disimpan sebagai payload notifikasi. Name diisi saat email disusun.
+type templateData struct {
+	Name    string         `json:"-"`
+	Event   *entity.Event  `json:"event"`
+	Ticket  *entity.Ticket `json:"ticket,omitempty"`
+	Changes EventChanges   `json:"changes"`
+}
+
+type notifier struct {
+	notificationRepo repository.NotificationRepository
+	userRepo         repository.UserRepository
+	ticketRepo       repository.TicketRepository
+	mailer           mailer.Mailer
+	templates        map[string]*template.Template
+	config           config.Config
+}
+
+func NewNotifier(
+	notificationRepo repository.NotificationRepository,
+	userRepo repository.UserRepository,
+	ticketRepo repository.TicketRepository,
+	mailer mailer.Mailer,
+	config config.Config,
+) Notifier {
+	loc, err := time.LoadLocation(config.NotificationTimezone)
+	if err != nil {
+		utils.Log.Warnf("Invalid notification timezone %q, using UTC: %v", config.NotificationTimezone, err)
+		loc = time.UTC
+	}
+
+	return &notifier{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		ticketRepo:       ticketRepo,
+		mailer:           mailer,
+		templates:        parseTemplates(loc),
+		config:           config,
+	}
+}
+
+func (n *notifier) WithTx(tx *gorm.DB) Notifier {
+	clone := *n
+	clone.notificationRepo = n.notificationRepo.WithTx(tx)
+	clone.ticketRepo = n.ticketRepo.WithTx(tx)
+	return &clone
+}
+
+// parseTemplates memuat template untuk setiap bahasa dan jenis notifikasi. Template
+// ikut di-embed ke binary sehingga kesalahan di dalamnya langsung gagal saat start.
+func parseTemplates(loc *time.Location) map[string]*template.Template {
+	templates := map[string]*template.Template{}
+	for _, language := range languages {
+		language := language
+		funcs := template.FuncMap{
+			"date":  func(t time.Time) string { return formatDate(t, language, loc) },
+			"money": func(value float64) string { return formatMoney(value, language) },
+		}
+
+		for _, notificationType := range notificationTypes {
+			path := fmt.Sprintf("templates/%s/%s.tmpl", language, notificationType)
+			templates[templateKey(language, notificationType)] = template.Must(
+				template.New(string(notificationType)).Funcs(funcs).ParseFS(templateFS, path))
+		}
+	}
+	return templates
+}
+
+func templateKey(language entity.Language, notificationType entity.NotificationType) string {
+	return string(language) + "/" + string(notificationType)
+}
+
+func (n *notifier) TicketPurchased(ticket *entity.Ticket) error {
+	return n.notifyTicketHolder(ticket, entity.TicketPurchasedNotification)
+}
+
+func (n *notifier) TicketCancelled(ticket *entity.Ticket) error {
+	if ticket.Price <= 0 {
+		return n.notifyTicketHolder(ticket, entity.TicketCancelledNotification)
+	}
+	return n.notifyTicketHolder(ticket, entity.TicketCancelledNotification, entity.TicketRefundedNotification)
+}
+
+func (n *notifier) EventReminder(ticket *entity.Ticket) error {
+	return n.notifyTicketHolder(ticket, entity.EventReminderNotification)
+}
+
+func (n *notifier) notifyTicketHolder(ticket *entity.Ticket, types ...entity.NotificationType) error {
+	notifications := make([]entity.Notification, 0, len(types))
+	for _, notificationType := range types {
+		notification, err := newNotification(ticket.UserID, notificationType, templateData{Event: &ticket.Event, Ticket: ticket})
+		if err != nil {
+			return err
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	return n.notificationRepo.WithTenant(ticket.OrganizationID.String()).CreateAll(notifications)
+}
+
+// EventChanged memberi tahu pemegang tiket jika nama, jadwal, atau lokasi event berubah
+func (n *notifier) EventChanged(before, after *entity.Event) error {
+	changes := EventChanges{
+		Before:   before,
+		Name:     before.Name != after.Name,
+		Schedule: !before.StartDate.Equal(after.StartDate) || !before.EndDate.Equal(after.EndDate),
+		Location: before.Location != after.Location,
+	}
+	if !changes.Name && !changes.Schedule && !changes.Location {
+		return nil
+	}
+
+	return n.notifyEventHolders(after, changes)
+}
+
+func (n *notifier) EventCancelled(event *entity.Event) error {
+	return n.notifyEventHolders(event, EventChanges{Before: event, Cancelled: true})
+}
+
+// notifyEventHolders menyimpan notifikasi untuk semua pemegang tiket sekaligus, sehingga
+// saat gagal tidak ada pemegang tiket yang sudah tercatat dan percobaan ulang tidak dobel
+func (n *notifier) notifyEventHolders(event *entity.Event, changes EventChanges) error {
+	organizationID := event.OrganizationID.String()
+
+	userIDs, err := n.ticketRepo.WithTenant(organizationID).FindHolderIDsByEventID(event.ID.String())
+	if err != nil {
+		return err
+	}
+
+	notifications := make([]entity.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		notification, err := newNotification(userID, entity.EventChangedNotification, templateData{Event: event, Changes: changes})
+		if err != nil {
+			return err
+		}
+		notifications = append(notifications, *notification)
+	}
+
+	return n.notificationRepo.WithTenant(organizationID).CreateAll(notifications)
+}
+
+func newNotification(userID uuid.UUID, notificationType entity.NotificationType, data templateData) (*entity.Notification, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &entity.Notification{
+		UserID:        userID,
+		Type:          notificationType,
+		Status:        entity.PendingNotification,
+		Payload:       string(payload),
+		NextAttemptAt: &now,
+	}, nil
+}
+
+// RunDue mengirim notifikasi yang jatuh tempo lintas organisasi memakai beberapa worker
+func (n *notifier) RunDue() error {
+	now := time.Now()
+
+	notifications, err := n.notificationRepo.FindDue(now, dispatchBatchSize)
+	if err != nil {
+		return err
+	}
+
+	claimed := make(chan *entity.Notification)
+	var wg sync.WaitGroup
+	for i := 0; i < workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for notification := range claimed {
+				n.deliver(notification)
+			}
+		}()
+	}
+
+	for i := range notifications {
+		notification := &notifications[i]
+
+		ok, err := n.notificationRepo.Claim(notification.ID, *notification.NextAttemptAt, now.Add(dispatchLease))
+		if err != nil {
+			close(claimed)
+			wg.Wait()
+			return err
+		}
+		if ok {
+			claimed <- notification
+		}
+	}
+
+	close(claimed)
+	wg.Wait()
+	return nil
+}
+
+// Start menjalankan pengiriman notifikasi berkala di background
+func (n *notifier) Start() {
+	if n.config.NotificationDispatchInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(n.config.NotificationDispatchInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := n.RunDue(); err != nil {
+				utils.Log.Errorf("Failed to dispatch notifications: %v", err)
+			}
+		}
+	}()
+}
+
+// deliver menyusun dan mengirim satu notifikasi lalu mencatat hasilnya. Notifikasi yang
+// tidak bisa disusun, misalnya karena user sudah dihapus, langsung ditandai gagal.
+func (n *notifier) deliver(notification *entity.Notification) {
+	notificationRepo := n.notificationRepo.WithTenant(notification.OrganizationID.String())
+	notification.Attempts++
+
+	message, err := n.prepare(notification)
+	if err == nil {
+		err = n.mailer.Send(*message)
+	}
+
+	switch {
+	case err == nil:
+		notification.Status = entity.SentNotification
+		notification.LastError = ""
+		notification.NextAttemptAt = nil
+	case message == nil || notification.Attempts >= n.config.NotificationMaxAttempts:
+		utils.Log.Errorf("Notification %s failed after %d attempts: %v", notification.ID, notification.Attempts, err)
+		notification.Status = entity.FailedNotification
+		notification.LastError = err.Error()
+		notification.NextAttemptAt = nil
+	default:
+		utils.Log.Errorf("Failed to send notification %s (attempt %d): %v", notification.ID, notification.Attempts, err)
+		next := time.Now().Add(n.retryDelay(notification.Attempts))
+		notification.Status = entity.PendingNotification
+		notification.LastError = err.Error()
+		notification.NextAttemptAt = &next
+	}
+
+	if err := notificationRepo.RecordAttempt(notification); err != nil {
+		utils.Log.Errorf("Failed to record notification %s: %v", notification.ID, err)
+	}
+}
+
+func (n *notifier) retryDelay(attempts int) time.Duration {
+	delay := n.config.NotificationRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
+
+// prepare memuat user dan menyusun email sesuai bahasanya dari payload notifikasi.
+// Penerima, bahasa, dan subject ikut dicatat di notifikasi.
+func (n *notifier) prepare(notification *entity.Notification) (*mailer.Message, error) {
+	var data templateData
+	if err := json.Unmarshal([]byte(notification.Payload), &data); err != nil {
+		return nil, fmt.Errorf("invalid notification payload: %w", err)
+	}
+
+	user, err := n.userRepo.WithTenant(notification.OrganizationID.String()).FindByID(notification.UserID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	if strings.HasSuffix(user.Email, entity.AnonymizedEmailDomain) {
+		return nil, errors.New("user was deleted")
+	}
+
+	language := user.Language
+	if language != entity.EnglishLanguage {
+		language = entity.IndonesianLanguage
+	}
+
+	data.Name = user.Name
+	tmpl := n.templates[templateKey(language, notification.Type)]
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return nil, fmt.Errorf("failed to render body: %w", err)
+	}
+
+	message := &mailer.Message{
+		To:      []string{user.Email},
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    strings.TrimSpace(body.String()),
+	}
+
+	if notification.Type == entity.TicketPurchasedNotification && data.Ticket != nil {
+		attachment, err := ticketQRAttachment(data.Ticket)
+		if err != nil {
+			return nil, err
+		}
+		message.Attachments = []mailer.Attachment{attachment}
+	}
+
+	notification.Language = language
+	notification.Recipient = user.Email
+	notification.Subject = message.Subject
+	return message, nil
+}
+
+// ticketQRAttachment membuat gambar QR berisi kode booking untuk dilampirkan ke email tiket
+func ticketQRAttachment(ticket *entity.Ticket) (mailer.Attachment, error) {
+	qr, err := utils.EncodeQR(ticket.BookingCode)
+	if err != nil {
+		return mailer.Attachment{}, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	image, err := qr.PNG(qrScale)
+	if err != nil {
+		return mailer.Attachment{}, fmt.Errorf("failed to render QR code: %w", err)
+	}
+
+	return mailer.Attachment{
+		Filename:    fmt.Sprintf("ticket-%s.png", ticket.BookingCode),
+		ContentType: "image/png",
+		Data:        image,
+	}, nil
+}
@@ -0,0 +1,355 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"event-ticketing/config"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// keysRefreshInterval membatasi seberapa sering JWKS diambil ulang saat kid tidak dikenal
+const keysRefreshInterval = time.Minute
+
+// IDTokenClaims berisi klaim ID token yang dipakai untuk login
+type IDTokenClaims struct {
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	Name          string   `json:"name"`
+	Nonce         string   `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// flexBool menerima true maupun "true" karena beberapa provider mengirim email_verified sebagai string
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	*b = flexBool(value == "true")
+	return nil
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Provider adalah client OpenID Connect untuk alur authorization code dengan PKCE.
+// Metadata discovery dan JWKS diambil saat pertama kali dibutuhkan lalu disimpan di memori.
+type Provider struct {
+	config config.OIDCProviderConfig
+	client *http.Client
+
+	mu            sync.Mutex
+	metadata      *discovery
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+func NewProvider(config config.OIDCProviderConfig) *Provider {
+	return &Provider{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewProviders membuat provider dari konfigurasi, dengan nama provider sebagai key
+func NewProviders(configs []config.OIDCProviderConfig) map[string]*Provider {
+	providers := make(map[string]*Provider, len(configs))
+	for _, providerConfig := range configs {
+		providers[providerConfig.Name] = NewProvider(providerConfig)
+	}
+	return providers
+}
+
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// CodeChallenge menghitung code_challenge S256 dari code verifier sesuai RFC 7636
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (p *Provider) AuthCodeURL(state, nonce, codeVerifier string) (string, error) {
+	metadata, err := p.discover()
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(metadata.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return metadata.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange menukar authorization code dengan token lalu memverifikasi ID token yang diterima
+func (p *Provider) Exchange(code, codeVerifier, nonce string) (*IDTokenClaims, error) {
+	metadata, err := p.discover()
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if p.config.ClientSecret != "" {
+		form.Set("client_secret", p.config.ClientSecret)
+	}
+
+	resp, err := p.client.PostForm(metadata.TokenEndpoint, form)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid token response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token exchange failed: %s %s", body.Error, body.ErrorDescription)
+	}
+
+	if body.IDToken == "" {
+		return nil, errors.New("token response does not contain an id_token")
+	}
+
+	return p.verify(body.IDToken, nonce)
+}
+
+func (p *Provider) verify(idToken, nonce string) (*IDTokenClaims, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "EdDSA"}))
+
+	claims := &IDTokenClaims{}
+	if _, err := parser.ParseWithClaims(idToken, claims, p.keyfunc); err != nil {
+		return nil, fmt.Errorf("invalid id_token: %v", err)
+	}
+
+	if claims.Issuer != p.config.Issuer {
+		return nil, errors.New("invalid id_token: unexpected issuer")
+	}
+
+	if !claims.VerifyAudience(p.config.ClientID, true) {
+		return nil, errors.New("invalid id_token: unexpected audience")
+	}
+
+	if claims.ExpiresAt == nil {
+		return nil, errors.New("invalid id_token: missing expiry")
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("invalid id_token: nonce mismatch")
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("invalid id_token: missing subject")
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	p.mu.Lock()
+	key, ok := p.lookupKey(kid)
+	stale := time.Since(p.keysFetchedAt) > keysRefreshInterval
+	p.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+
+	// Provider mungkin baru merotasi key-nya
+	if !stale {
+		return nil, fmt.Errorf("unknown signing key: %s", kid)
+	}
+
+	if err := p.fetchKeys(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key: %s", kid)
+}
+
+// lookupKey mencari key berdasarkan kid. Token tanpa kid bisa dipakai jika provider hanya
+// memiliki satu key. Pemanggil harus memegang p.mu.
+func (p *Provider) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if key, ok := p.keys[kid]; ok {
+		return key, true
+	}
+
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+func (p *Provider) discover() (*discovery, error) {
+	p.mu.Lock()
+	metadata := p.metadata
+	p.mu.Unlock()
+
+	if metadata != nil {
+		return metadata, nil
+	}
+
+	metadata = &discovery{}
+	if err := p.getJSON(strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", metadata); err != nil {
+		return nil, err
+	}
+
+	if metadata.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("issuer mismatch in discovery document: %s", metadata.Issuer)
+	}
+
+	p.mu.Lock()
+	p.metadata = metadata
+	p.mu.Unlock()
+
+	return metadata, nil
+}
+
+func (p *Provider) fetchKeys() error {
+	metadata, err := p.discover()
+	if err != nil {
+		return err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(metadata.JWKSURI, &set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *Provider) getJSON(endpoint string, target interface{}) error {
+	resp, err := p.client.Get(endpoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
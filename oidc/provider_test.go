@@ -0,0 +1,38 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"event-ticketing/config"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestKeyfuncAcceptsTokenWithoutKidWithFreshKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// JWKS baru saja diambil sehingga keyfunc tidak akan mengambilnya ulang
+	provider := NewProvider(config.OIDCProviderConfig{Name: "test", Issuer: "https://issuer.example.com"})
+	provider.keys = map[string]crypto.PublicKey{"only": &key.PublicKey}
+	provider.keysFetchedAt = time.Now()
+
+	token := jwt.New(jwt.SigningMethodRS256)
+	got, err := provider.keyfunc(token)
+	if err != nil {
+		t.Fatalf("expected the only key to be used for a token without kid: %v", err)
+	}
+	if got != &key.PublicKey {
+		t.Fatal("unexpected key returned")
+	}
+
+	token.Header["kid"] = "other"
+	if _, err := provider.keyfunc(token); err == nil {
+		t.Fatal("expected unknown kid to be rejected")
+	}
+}
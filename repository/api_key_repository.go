@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"event-ticketing/utils"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+// lastUsedResolution membatasi penulisan last_used_at agar tidak terjadi di setiap request
+const lastUsedResolution = time.Minute
+
+type APIKeyRepository interface {
+	Create(key *entity.APIKey) error
+	FindByID(id string) (*entity.APIKey, error)
+	FindByHash(hash string) (*entity.APIKey, error)
+	FindAll(params utils.PaginationParams) ([]entity.APIKey, int64, error)
+	Revoke(id string) (bool, error)
+	RevokeByUserID(userID string) error
+	TouchLastUsed(id string, ip string) error
+	WithTenant(organizationID string) APIKeyRepository
+}
+
+type apiKeyRepository struct {
+	db             *gorm.DB
+	organizationID uuid.UUID
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) WithTenant(organizationID string) APIKeyRepository {
+	return &apiKeyRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *apiKeyRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
+}
+
+func (r *apiKeyRepository) Create(key *entity.APIKey) error {
+	key.OrganizationID = r.organizationID
+	return r.db.Create(key).Error
+}
+
+func (r *apiKeyRepository) FindByID(id string) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := r.scoped().Where("id = ?", id).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("api key not found")
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByHash tidak dibatasi tenant karena organisasi justru ditentukan oleh API key itu sendiri
+func (r *apiKeyRepository) FindByHash(hash string) (*entity.APIKey, error) {
+	var key entity.APIKey
+	err := r.db.Where("key_hash = ?", hash).First(&key).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("api key not found")
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) FindAll(params utils.PaginationParams) ([]entity.APIKey, int64, error) {
+	var keys []entity.APIKey
+	var count int64
+
+	if err := r.scoped().Model(&entity.APIKey{}).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.scoped().Order("created_at DESC").Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&keys).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return keys, count, nil
+}
+
+func (r *apiKeyRepository) Revoke(id string) (bool, error) {
+	result := r.scoped().Model(&entity.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *apiKeyRepository) RevokeByUserID(userID string) error {
+	return r.scoped().Model(&entity.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *apiKeyRepository) TouchLastUsed(id string, ip string) error {
+	now := time.Now()
+	return r.db.Model(&entity.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-lastUsedResolution)).
+		Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": ip}).Error
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"event-ticketing/entity"
+	"event-ticketing/utils"
+	"strings"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter berisi filter opsional untuk mencari audit log
+type AuditLogFilter struct {
+	ActorID    string
+	Action     string
+	EntityType string
+	EntityID   string
+	RequestID  string
+	From       string
+	To         string
+}
+
+type AuditLogRepository interface {
+	Create(log *entity.AuditLog) error
+	FindAll(params utils.PaginationParams, filter AuditLogFilter) ([]entity.AuditLog, int64, error)
+	AnonymizeUser(userID, email string) error
+	WithTenant(organizationID string) AuditLogRepository
+}
+
+type auditLogRepository struct {
+	db             *gorm.DB
+	organizationID uuid.UUID
+}
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) WithTenant(organizationID string) AuditLogRepository {
+	return &auditLogRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *auditLogRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
+}
+
+func (r *auditLogRepository) Create(log *entity.AuditLog) error {
+	log.OrganizationID = r.organizationID
+	return r.db.Create(log).Error
+}
+
+func (r *auditLogRepository) FindAll(params utils.PaginationParams, filter AuditLogFilter) ([]entity.AuditLog, int64, error) {
+	var logs []entity.AuditLog
+	var count int64
+
+	query := r.scoped().Model(&entity.AuditLog{})
+
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+
+	if filter.From != "" {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+
+	if filter.To != "" {
+		query = query.Where("created_at <= ?", filter.To)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC").Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return logs, count, nil
+}
+
+// AnonymizeUser mengganti email dan IP milik user pada audit log agar jejaknya
+// tetap ada tanpa menyimpan data pribadi. Nama dan email di kolom changes dari
+// audit log lama, sebelum nilainya disamarkan saat dicatat, juga ikut dihapus.
+func (r *auditLogRepository) AnonymizeUser(userID, email string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Scopes(tenantScope(r.organizationID)).Model(&entity.AuditLog{}).
+			Where("entity_type = ? AND entity_id = ?", "account", strings.ToLower(email)).
+			Updates(map[string]interface{}{"entity_type": "user", "entity_id": userID, "ip": ""}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Scopes(tenantScope(r.organizationID)).Model(&entity.AuditLog{}).
+			Where("entity_type = ? AND entity_id = ? AND changes <> ''", "user", userID).
+			Update("changes", gorm.Expr("JSON_REMOVE(changes, '$.name', '$.email', '$.pending_email')")).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Scopes(tenantScope(r.organizationID)).Model(&entity.AuditLog{}).
+			Where("actor_id = ? OR (entity_type = ? AND entity_id = ?)", userID, "user", userID).
+			Update("ip", "").Error
+	})
+}
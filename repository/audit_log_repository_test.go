@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"encoding/json"
+	"event-ticketing/entity"
+	"testing"
+)
+
+func TestAuditLogRepositoryAnonymizeUser(t *testing.T) {
+	db := openTestDB(t)
+
+	organization := seedOrganization(t, db)
+	repo := NewAuditLogRepository(db).WithTenant(organization.ID.String())
+	user := seedUser(t, db, organization.ID)
+	other := seedUser(t, db, organization.ID)
+
+	changes := `{"name":{"before":"Old Name","after":"New Name"},"email":{"before":null,"after":"user@example.com"},"language":{"before":"id","after":"en"}}`
+	logs := []*entity.AuditLog{
+		{Action: "auth.profile_updated", EntityType: "user", EntityID: user.ID.String(), IP: "203.0.113.7", Changes: changes},
+		{Action: "auth.login", EntityType: "user", EntityID: user.ID.String(), IP: "203.0.113.7"},
+		{Action: "auth.profile_updated", EntityType: "user", EntityID: other.ID.String(), IP: "203.0.113.8", Changes: changes},
+	}
+	for _, log := range logs {
+		if err := repo.Create(log); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := repo.AnonymizeUser(user.ID.String(), user.Email); err != nil {
+		t.Fatal(err)
+	}
+
+	var anonymized, untouched entity.AuditLog
+	if err := db.First(&anonymized, "id = ?", logs[0].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.First(&untouched, "id = ?", logs[2].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(anonymized.Changes), &fields); err != nil {
+		t.Fatalf("expected changes to stay valid JSON, got %q: %v", anonymized.Changes, err)
+	}
+	if _, ok := fields["name"]; ok {
+		t.Fatalf("expected name to be removed from changes, got %s", anonymized.Changes)
+	}
+	if _, ok := fields["email"]; ok {
+		t.Fatalf("expected email to be removed from changes, got %s", anonymized.Changes)
+	}
+	if _, ok := fields["language"]; !ok {
+		t.Fatalf("expected non-personal changes to be kept, got %s", anonymized.Changes)
+	}
+	if anonymized.IP != "" {
+		t.Fatalf("expected IP to be cleared, got %q", anonymized.IP)
+	}
+
+	if untouched.Changes != changes || untouched.IP == "" {
+		t.Fatal("expected audit logs of other users to be left untouched")
+	}
+}
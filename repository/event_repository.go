@@ -6,6 +6,7 @@ import (
 	"event-ticketing/utils"
 	"gorm.io/gorm/clause"
 
+	"github.com/gofrs/uuid/v5"
 	"gorm.io/gorm"
 )
 
@@ -18,24 +19,43 @@ type EventRepository interface {
 	Update(event *entity.Event) error
 	Delete(id string) error
 	CountTicketsSold(eventID string) (int, error)
+	GetCapacitySummary() (int64, int, error)
+	AdjustTicketsSold(eventID string, delta int) error
+	FindTicketsSoldDrift() ([]TicketsSoldDrift, error)
+	ReconcileTicketsSold(eventID string) error
 	WithTx(tx *gorm.DB) EventRepository
+	WithTenant(organizationID string) EventRepository
+}
+
+// TicketsSoldDrift adalah event yang counter tickets_sold-nya tidak sama dengan jumlah tiket terjual
+type TicketsSoldDrift struct {
+	EventID        uuid.UUID
+	OrganizationID uuid.UUID
+	Stored         int
+	Actual         int
 }
 
 type eventRepository struct {
-	db *gorm.DB
+	db             *gorm.DB
+	organizationID uuid.UUID
 }
 
 func NewEventRepository(db *gorm.DB) EventRepository {
-	return &eventRepository{db}
+	return &eventRepository{db: db}
+}
+
+func (r *eventRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
 }
 
 func (r *eventRepository) Create(event *entity.Event) error {
+	event.OrganizationID = r.organizationID
 	return r.db.Create(event).Error
 }
 
 func (r *eventRepository) FindByID(id string) (*entity.Event, error) {
 	var event entity.Event
-	err := r.db.Where("id = ?", id).First(&event).Error
+	err := r.scoped().Where("id = ?", id).First(&event).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("event not found")
@@ -43,18 +63,12 @@ func (r *eventRepository) FindByID(id string) (*entity.Event, error) {
 		return nil, err
 	}
 
-	soldTickets, err := r.CountTicketsSold(id)
-	if err != nil {
-		return nil, err
-	}
-	event.TicketsSold = soldTickets
-
 	return &event, nil
 }
 
 func (r *eventRepository) FindByIDForUpdate(id string) (*entity.Event, error) {
 	var event entity.Event
-	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+	err := r.scoped().Clauses(clause.Locking{Strength: "UPDATE"}).
 		Where("id = ?", id).
 		First(&event).Error
 
@@ -65,24 +79,22 @@ func (r *eventRepository) FindByIDForUpdate(id string) (*entity.Event, error) {
 		return nil, err
 	}
 
-	soldTickets, err := r.CountTicketsSold(id)
-	if err != nil {
-		return nil, err
-	}
-	event.TicketsSold = soldTickets
-
 	return &event, nil
 }
 
 func (r *eventRepository) WithTx(tx *gorm.DB) EventRepository {
-	return &eventRepository{db: tx}
+	return &eventRepository{db: tx, organizationID: r.organizationID}
+}
+
+func (r *eventRepository) WithTenant(organizationID string) EventRepository {
+	return &eventRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
 }
 
 func (r *eventRepository) FindAll(params utils.PaginationParams, keyword, status, startDate, endDate string) ([]entity.Event, int64, error) {
 	var events []entity.Event
 	var count int64
 
-	query := r.db.Model(&entity.Event{})
+	query := r.scoped().Model(&entity.Event{})
 	if keyword != "" {
 		query = query.Where("name LIKE ? OR description LIKE ? OR location LIKE ?",
 			"%"+keyword+"%", "%"+keyword+"%", "%"+keyword+"%")
@@ -108,20 +120,12 @@ func (r *eventRepository) FindAll(params utils.PaginationParams, keyword, status
 		return nil, 0, err
 	}
 
-	for i, event := range events {
-		soldTickets, err := r.CountTicketsSold(event.ID.String())
-		if err != nil {
-			return nil, 0, err
-		}
-		events[i].TicketsSold = soldTickets
-	}
-
 	return events, count, nil
 }
 
 func (r *eventRepository) FindByName(name string) (*entity.Event, error) {
 	var event entity.Event
-	err := r.db.Where("name = ?", name).First(&event).Error
+	err := r.scoped().Where("name = ?", name).First(&event).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("event not found")
@@ -132,12 +136,16 @@ func (r *eventRepository) FindByName(name string) (*entity.Event, error) {
 }
 
 func (r *eventRepository) Update(event *entity.Event) error {
-	return r.db.Save(event).Error
+	if event.OrganizationID != r.organizationID {
+		return errors.New("event not found")
+	}
+	// tickets_sold hanya diubah lewat AdjustTicketsSold agar tidak tertimpa nilai lama
+	return r.db.Omit("tickets_sold").Save(event).Error
 }
 
 func (r *eventRepository) Delete(id string) error {
 	var ticketCount int64
-	err := r.db.Model(&entity.Ticket{}).Where("event_id = ? AND status = ?", id, entity.PurchasedTicket).Count(&ticketCount).Error
+	err := r.scoped().Model(&entity.Ticket{}).Where("event_id = ? AND status = ?", id, entity.PurchasedTicket).Count(&ticketCount).Error
 	if err != nil {
 		return err
 	}
@@ -146,11 +154,62 @@ func (r *eventRepository) Delete(id string) error {
 		return errors.New("cannot delete event with sold tickets")
 	}
 
-	return r.db.Where("id = ?", id).Delete(&entity.Event{}).Error
+	return r.scoped().Where("id = ?", id).Delete(&entity.Event{}).Error
 }
 
 func (r *eventRepository) CountTicketsSold(eventID string) (int, error) {
 	var count int64
-	err := r.db.Model(&entity.Ticket{}).Where("event_id = ? AND status = ?", eventID, entity.PurchasedTicket).Count(&count).Error
+	err := r.scoped().Model(&entity.Ticket{}).Where("event_id = ? AND status = ?", eventID, entity.PurchasedTicket).Count(&count).Error
 	return int(count), err
 }
+
+// GetCapacitySummary mengembalikan jumlah event dan total kapasitasnya
+func (r *eventRepository) GetCapacitySummary() (int64, int, error) {
+	var summary struct {
+		Events   int64
+		Capacity int
+	}
+	err := r.scoped().Model(&entity.Event{}).
+		Select("COUNT(*) AS events, COALESCE(SUM(capacity), 0) AS capacity").
+		Scan(&summary).Error
+	return summary.Events, summary.Capacity, err
+}
+
+// AdjustTicketsSold menambah atau mengurangi counter tiket terjual. Dipanggil di dalam
+// transaksi pembelian atau pembatalan yang sama dengan perubahan status tiket.
+func (r *eventRepository) AdjustTicketsSold(eventID string, delta int) error {
+	return r.scoped().Model(&entity.Event{}).
+		Where("id = ?", eventID).
+		UpdateColumn("tickets_sold", gorm.Expr("tickets_sold + ?", delta)).Error
+}
+
+// FindTicketsSoldDrift membandingkan counter dengan jumlah tiket terjual di semua organisasi
+func (r *eventRepository) FindTicketsSoldDrift() ([]TicketsSoldDrift, error) {
+	var drifts []TicketsSoldDrift
+	err := r.db.Model(&entity.Event{}).
+		Select("events.id AS event_id, events.organization_id, events.tickets_sold AS stored, COUNT(tickets.id) AS actual").
+		Joins("LEFT JOIN tickets ON tickets.event_id = events.id AND tickets.status = ? AND tickets.deleted_at IS NULL", entity.PurchasedTicket).
+		Group("events.id, events.organization_id, events.tickets_sold").
+		Having("events.tickets_sold <> COUNT(tickets.id)").
+		Scan(&drifts).Error
+	return drifts, err
+}
+
+// ReconcileTicketsSold menghitung ulang counter di bawah lock baris event
+func (r *eventRepository) ReconcileTicketsSold(eventID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		repo := r.WithTx(tx).(*eventRepository)
+		if _, err := repo.FindByIDForUpdate(eventID); err != nil {
+			return err
+		}
+
+		sold, err := repo.CountTicketsSold(eventID)
+		if err != nil {
+			return err
+		}
+
+		return repo.scoped().Model(&entity.Event{}).
+			Where("id = ?", eventID).
+			UpdateColumn("tickets_sold", sold).Error
+	})
+}
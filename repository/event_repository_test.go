@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+// BenchmarkBuyTicket membandingkan pengecekan stok saat pembelian: counter tickets_sold
+// pada baris event yang dikunci, dengan COUNT(*) atas tabel tickets seperti sebelumnya.
+// Jalankan dengan TEST_DATABASE_DSN, misalnya:
+//
+//	go test ./repository -run '^$' -bench BuyTicket
+func BenchmarkBuyTicket(b *testing.B) {
+	for _, sold := range []int{100, 10000} {
+		b.Run(fmt.Sprintf("counter/sold=%d", sold), func(b *testing.B) {
+			benchmarkBuyTicket(b, sold, false)
+		})
+		b.Run(fmt.Sprintf("count/sold=%d", sold), func(b *testing.B) {
+			benchmarkBuyTicket(b, sold, true)
+		})
+	}
+}
+
+func benchmarkBuyTicket(b *testing.B, sold int, countTickets bool) {
+	db := openTestDB(b)
+
+	organization := seedOrganization(b, db)
+	user := seedUser(b, db, organization.ID)
+	event := seedEvent(b, db, user, sold+b.N+1, 50000)
+	seedSoldTickets(b, db, event, user.ID, sold)
+
+	eventID := event.ID.String()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			eventRepo := NewEventRepository(tx).WithTenant(organization.ID.String())
+			ticketRepo := NewTicketRepository(tx).WithTenant(organization.ID.String())
+
+			locked, err := eventRepo.FindByIDForUpdate(eventID)
+			if err != nil {
+				return err
+			}
+
+			if countTickets {
+				if locked.TicketsSold, err = eventRepo.CountTicketsSold(eventID); err != nil {
+					return err
+				}
+			}
+
+			if !locked.HasAvailableTickets(1) {
+				return errors.New("event is sold out")
+			}
+
+			ticket := &entity.Ticket{
+				EventID:      locked.ID,
+				UserID:       user.ID,
+				PurchaseDate: time.Now(),
+				Status:       entity.PurchasedTicket,
+				BookingCode:  "BENCH-" + uuid.Must(uuid.NewV4()).String()[:13],
+				Price:        locked.Price,
+			}
+			if err := ticketRepo.Create(ticket); err != nil {
+				return err
+			}
+
+			return eventRepo.AdjustTicketsSold(eventID, 1)
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// seedSoldTickets menambahkan tiket terjual secara massal beserta counter event-nya
+func seedSoldTickets(b *testing.B, db *gorm.DB, event *entity.Event, userID uuid.UUID, count int) {
+	b.Helper()
+
+	tickets := make([]entity.Ticket, count)
+	for i := range tickets {
+		tickets[i] = entity.Ticket{
+			OrganizationID: event.OrganizationID,
+			EventID:        event.ID,
+			UserID:         userID,
+			PurchaseDate:   time.Now(),
+			Status:         entity.PurchasedTicket,
+			BookingCode:    "SEED-" + uuid.Must(uuid.NewV4()).String()[:13],
+			Price:          event.Price,
+		}
+	}
+	if err := db.CreateInBatches(tickets, 500).Error; err != nil {
+		b.Fatalf("failed to seed tickets: %v", err)
+	}
+
+	err := NewEventRepository(db).WithTenant(event.OrganizationID.String()).AdjustTicketsSold(event.ID.String(), count)
+	if err != nil {
+		b.Fatalf("failed to seed tickets sold: %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+package repository
+
+// Helper test yang dipakai test di package repository_test, misalnya test laporan
+// dan pengingat yang menggabungkan repository dengan service.
+var (
+	OpenTestDB        = openTestDB
+	SeedReportFixture = seedReportFixture
+	SeedOrganization  = seedOrganization
+	SeedUser          = seedUser
+	SeedEvent         = seedEvent
+	SeedTicket        = seedTicket
+)
+
+type ReportFixture = reportFixture
+
+func (f ReportFixture) OrganizationID() string {
+	return f.organization.ID.String()
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginThrottleRepository interface {
+	FindByKeys(keys ...string) ([]entity.LoginThrottle, error)
+	RecordFailure(key string, window time.Duration) (*entity.LoginThrottle, error)
+	SetLockedUntil(key string, lockedUntil time.Time) error
+	Reset(key string) error
+	WithTenant(organizationID string) LoginThrottleRepository
+}
+
+type loginThrottleRepository struct {
+	db             *gorm.DB
+	organizationID uuid.UUID
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) LoginThrottleRepository {
+	return &loginThrottleRepository{db: db}
+}
+
+func (r *loginThrottleRepository) WithTenant(organizationID string) LoginThrottleRepository {
+	return &loginThrottleRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *loginThrottleRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
+}
+
+func (r *loginThrottleRepository) FindByKeys(keys ...string) ([]entity.LoginThrottle, error) {
+	var throttles []entity.LoginThrottle
+	err := r.scoped().Where("throttle_key IN ?", keys).Find(&throttles).Error
+	return throttles, err
+}
+
+// RecordFailure menambah hitungan gagal secara atomik. Hitungan dimulai ulang
+// jika kegagalan terakhir sudah lebih lama dari window.
+func (r *loginThrottleRepository) RecordFailure(key string, window time.Duration) (*entity.LoginThrottle, error) {
+	now := time.Now()
+	throttle := entity.LoginThrottle{
+		OrganizationID: r.organizationID,
+		ThrottleKey:    key,
+		Failures:       1,
+		LastFailedAt:   now,
+	}
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "organization_id"}, {Name: "throttle_key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":       gorm.Expr("IF(last_failed_at < ?, 1, failures + 1)", now.Add(-window)),
+			"last_failed_at": now,
+		}),
+	}).Create(&throttle).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var stored entity.LoginThrottle
+	err = r.scoped().Where("throttle_key = ?", key).First(&stored).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("login throttle not found")
+		}
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (r *loginThrottleRepository) SetLockedUntil(key string, lockedUntil time.Time) error {
+	return r.scoped().Model(&entity.LoginThrottle{}).
+		Where("throttle_key = ?", key).
+		Update("locked_until", lockedUntil).Error
+}
+
+func (r *loginThrottleRepository) Reset(key string) error {
+	return r.scoped().Unscoped().Where("throttle_key = ?", key).Delete(&entity.LoginThrottle{}).Error
+}
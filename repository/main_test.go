@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"event-ticketing/config"
+	"event-ticketing/entity"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	testDB      *gorm.DB
+	testDBErr   error
+	testDBSetup sync.Once
+)
+
+// openTestDB membuka database MySQL dari TEST_DATABASE_DSN, misalnya
+// "root:password@tcp(127.0.0.1:3306)/ticketing_test?parseTime=true&loc=Local".
+// Test dilewati jika variabel tersebut tidak diset. Setiap test membuat organisasinya
+// sendiri sehingga data antar test tidak saling mengganggu.
+func openTestDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	testDBSetup.Do(func() {
+		testDB, testDBErr = gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+		if testDBErr == nil {
+			testDBErr = config.Migrate(testDB)
+		}
+	})
+	if testDBErr != nil {
+		t.Fatalf("failed to open test database: %v", testDBErr)
+	}
+	return testDB
+}
+
+func seedOrganization(t testing.TB, db *gorm.DB) *entity.Organization {
+	t.Helper()
+
+	organization := &entity.Organization{Name: "org-" + uuid.Must(uuid.NewV4()).String()}
+	if err := db.Create(organization).Error; err != nil {
+		t.Fatalf("failed to seed organization: %v", err)
+	}
+	return organization
+}
+
+func seedUser(t testing.TB, db *gorm.DB, organizationID uuid.UUID) *entity.User {
+	t.Helper()
+
+	user := &entity.User{
+		Name:     "Test User",
+		Email:    uuid.Must(uuid.NewV4()).String() + "@example.com",
+		Password: "password",
+		Role:     entity.UserRole,
+		Language: entity.IndonesianLanguage,
+	}
+	if err := NewUserRepository(db).WithTenant(organizationID.String()).Create(user); err != nil {
+		t.Fatalf("failed to seed user: %v", err)
+	}
+	return user
+}
+
+func seedEvent(t testing.TB, db *gorm.DB, creator *entity.User, capacity int, price float64) *entity.Event {
+	t.Helper()
+
+	start := time.Now().Add(7 * 24 * time.Hour).Truncate(time.Second)
+	event := &entity.Event{
+		Name:      "Event " + uuid.Must(uuid.NewV4()).String(),
+		StartDate: start,
+		EndDate:   start.Add(3 * time.Hour),
+		Capacity:  capacity,
+		Price:     price,
+		Status:    entity.ActiveEvent,
+		Location:  "Jakarta",
+		CreatedBy: creator.ID,
+	}
+	if err := NewEventRepository(db).WithTenant(creator.OrganizationID.String()).Create(event); err != nil {
+		t.Fatalf("failed to seed event: %v", err)
+	}
+	return event
+}
+
+func seedTicket(t testing.TB, db *gorm.DB, event *entity.Event, userID uuid.UUID, status entity.TicketStatus) *entity.Ticket {
+	t.Helper()
+
+	ticket := &entity.Ticket{
+		EventID:      event.ID,
+		UserID:       userID,
+		PurchaseDate: time.Now(),
+		Status:       status,
+		BookingCode:  "TKT-" + uuid.Must(uuid.NewV4()).String()[:13],
+		Price:        event.Price,
+	}
+	if err := NewTicketRepository(db).WithTenant(event.OrganizationID.String()).Create(ticket); err != nil {
+		t.Fatalf("failed to seed ticket: %v", err)
+	}
+	return ticket
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"event-ticketing/entity"
+	"event-ticketing/utils"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	Create(notification *entity.Notification) error
+	CreateAll(notifications []entity.Notification) error
+	FindDue(now time.Time, limit int) ([]entity.Notification, error)
+	Claim(id uuid.UUID, previousNextAttemptAt, leaseUntil time.Time) (bool, error)
+	RecordAttempt(notification *entity.Notification) error
+	FindAllByUserID(userID string, params utils.PaginationParams) ([]entity.Notification, int64, error)
+	DeleteByUserID(userID string) error
+	WithTx(tx *gorm.DB) NotificationRepository
+	WithTenant(organizationID string) NotificationRepository
+}
+
+type notificationRepository struct {
+	db             *gorm.DB
+	organizationID uuid.UUID
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) WithTx(tx *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: tx, organizationID: r.organizationID}
+}
+
+func (r *notificationRepository) WithTenant(organizationID string) NotificationRepository {
+	return &notificationRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *notificationRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
+}
+
+func (r *notificationRepository) Create(notification *entity.Notification) error {
+	notification.OrganizationID = r.organizationID
+	return r.db.Create(notification).Error
+}
+
+// CreateAll menyimpan banyak notifikasi sekaligus dalam satu transaksi
+func (r *notificationRepository) CreateAll(notifications []entity.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	for i := range notifications {
+		notifications[i].OrganizationID = r.organizationID
+	}
+	return r.db.CreateInBatches(notifications, 500).Error
+}
+
+// FindDue mencari notifikasi pending lintas organisasi yang sudah waktunya dikirim
+func (r *notificationRepository) FindDue(now time.Time, limit int) ([]entity.Notification, error) {
+	var notifications []entity.Notification
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", entity.PendingNotification, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+// Claim menunda notifikasi sampai leaseUntil hanya jika belum diambil worker lain. Jika proses
+// mati sebelum hasilnya dicatat, notifikasi dikirim lagi setelah lease habis.
+func (r *notificationRepository) Claim(id uuid.UUID, previousNextAttemptAt, leaseUntil time.Time) (bool, error) {
+	result := r.db.Model(&entity.Notification{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", id, entity.PendingNotification, previousNextAttemptAt).
+		Update("next_attempt_at", leaseUntil)
+	return result.RowsAffected == 1, result.Error
+}
+
+// RecordAttempt mencatat hasil percobaan kirim beserta penerima, subject, dan jadwal
+// percobaan berikutnya. sent_at diisi saat status menjadi sent.
+func (r *notificationRepository) RecordAttempt(notification *entity.Notification) error {
+	updates := map[string]interface{}{
+		"status":          notification.Status,
+		"attempts":        notification.Attempts,
+		"last_error":      notification.LastError,
+		"language":        notification.Language,
+		"recipient":       notification.Recipient,
+		"subject":         notification.Subject,
+		"next_attempt_at": notification.NextAttemptAt,
+	}
+	if notification.Status == entity.SentNotification {
+		updates["sent_at"] = time.Now()
+	}
+
+	return r.db.Model(&entity.Notification{}).Where("id = ?", notification.ID).Updates(updates).Error
+}
+
+func (r *notificationRepository) FindAllByUserID(userID string, params utils.PaginationParams) ([]entity.Notification, int64, error) {
+	var notifications []entity.Notification
+	var count int64
+
+	query := r.scoped().Model(&entity.Notification{}).Where("user_id = ?", userID)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.scoped().Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Offset(params.GetOffset()).
+		Limit(params.GetLimit()).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, count, nil
+}
+
+// DeleteByUserID menghapus permanen log notifikasi karena berisi alamat email user
+func (r *notificationRepository) DeleteByUserID(userID string) error {
+	return r.scoped().Unscoped().Where("user_id = ?", userID).Delete(&entity.Notification{}).Error
+}
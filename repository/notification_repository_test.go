@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"event-ticketing/entity"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func TestNotificationRepositoryClaimsDueNotificationsOnce(t *testing.T) {
+	db := openTestDB(t)
+	organization := seedOrganization(t, db)
+	user := seedUser(t, db, organization.ID)
+	repo := NewNotificationRepository(db).WithTenant(organization.ID.String())
+
+	now := time.Now().Truncate(time.Second)
+	dueAt := now.Add(-time.Minute)
+	laterAt := now.Add(time.Hour)
+	notifications := []entity.Notification{
+		{UserID: user.ID, Type: entity.EventReminderNotification, Status: entity.PendingNotification, NextAttemptAt: &dueAt},
+		{UserID: user.ID, Type: entity.EventReminderNotification, Status: entity.PendingNotification, NextAttemptAt: &laterAt},
+	}
+	if err := repo.CreateAll(notifications); err != nil {
+		t.Fatalf("failed to create notifications: %v", err)
+	}
+	due, later := notifications[0], notifications[1]
+
+	found := findDueIDs(t, repo, now)
+	if !found[due.ID] || found[later.ID] {
+		t.Fatalf("FindDue() found due=%v later=%v, want only the due notification", found[due.ID], found[later.ID])
+	}
+
+	lease := now.Add(5 * time.Minute)
+	if ok, err := repo.Claim(due.ID, dueAt, lease); err != nil || !ok {
+		t.Fatalf("first claim: ok=%v err=%v, want claimed", ok, err)
+	}
+	if ok, err := repo.Claim(due.ID, dueAt, lease); err != nil || ok {
+		t.Fatalf("second claim: ok=%v err=%v, want not claimed", ok, err)
+	}
+	if found := findDueIDs(t, repo, now); found[due.ID] {
+		t.Fatal("claimed notification is still due before its lease expires")
+	}
+
+	due.Status = entity.SentNotification
+	due.Attempts = 1
+	due.NextAttemptAt = nil
+	if err := repo.RecordAttempt(&due); err != nil {
+		t.Fatalf("failed to record attempt: %v", err)
+	}
+	if found := findDueIDs(t, repo, lease.Add(time.Minute)); found[due.ID] {
+		t.Fatal("sent notification is due again after its lease expires")
+	}
+
+	var stored entity.Notification
+	if err := db.First(&stored, "id = ?", due.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != entity.SentNotification || stored.SentAt == nil || stored.NextAttemptAt != nil {
+		t.Fatalf("stored notification = status %s, sent_at %v, next_attempt_at %v", stored.Status, stored.SentAt, stored.NextAttemptAt)
+	}
+}
+
+func findDueIDs(t *testing.T, repo NotificationRepository, now time.Time) map[uuid.UUID]bool {
+	t.Helper()
+
+	notifications, err := repo.FindDue(now, 1000)
+	if err != nil {
+		t.Fatalf("FindDue() failed: %v", err)
+	}
+
+	ids := make(map[uuid.UUID]bool, len(notifications))
+	for _, notification := range notifications {
+		ids[notification.ID] = true
+	}
+	return ids
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type OIDCRepository interface {
+	CreateState(state *entity.OIDCState) error
+	ConsumeState(provider, hash string) (*entity.OIDCState, error)
+	FindIdentity(provider, subject string) (*entity.UserIdentity, error)
+	FindIdentitiesByUserID(userID string) ([]entity.UserIdentity, error)
+	CreateIdentity(identity *entity.UserIdentity) error
+	DeleteIdentitiesByUserID(userID string) error
+	WithTenant(organizationID string) OIDCRepository
+}
+
+type oidcRepository struct {
+	db             *gorm.DB
+	organizationID uuid.UUID
+}
+
+func NewOIDCRepository(db *gorm.DB) OIDCRepository {
+	return &oidcRepository{db: db}
+}
+
+func (r *oidcRepository) WithTenant(organizationID string) OIDCRepository {
+	return &oidcRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *oidcRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
+}
+
+func (r *oidcRepository) CreateState(state *entity.OIDCState) error {
+	state.OrganizationID = r.organizationID
+	return r.db.Create(state).Error
+}
+
+// ConsumeState mengambil lalu menghapus state secara atomik agar callback tidak bisa diputar ulang.
+// State tidak dibatasi tenant karena callback dari provider tidak membawa header organisasi.
+func (r *oidcRepository) ConsumeState(provider, hash string) (*entity.OIDCState, error) {
+	var state entity.OIDCState
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("provider = ? AND state_hash = ?", provider, hash).First(&state).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("id = ?", state.ID).Delete(&entity.OIDCState{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// Sekalian bersihkan state yang tidak pernah diselesaikan
+		return tx.Unscoped().Where("expires_at < ?", time.Now()).Delete(&entity.OIDCState{}).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("invalid or expired state")
+		}
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *oidcRepository) FindIdentity(provider, subject string) (*entity.UserIdentity, error) {
+	var identity entity.UserIdentity
+	err := r.scoped().Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("identity not found")
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *oidcRepository) FindIdentitiesByUserID(userID string) ([]entity.UserIdentity, error) {
+	var identities []entity.UserIdentity
+	err := r.scoped().Where("user_id = ?", userID).Find(&identities).Error
+	return identities, err
+}
+
+func (r *oidcRepository) CreateIdentity(identity *entity.UserIdentity) error {
+	identity.OrganizationID = r.organizationID
+	return r.db.Create(identity).Error
+}
+
+func (r *oidcRepository) DeleteIdentitiesByUserID(userID string) error {
+	return r.scoped().Unscoped().Where("user_id = ?", userID).Delete(&entity.UserIdentity{}).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"errors"
+	"event-ticketing/entity"
+
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository interface {
+	Create(organization *entity.Organization) error
+	FindByID(id string) (*entity.Organization, error)
+	FindByName(name string) (*entity.Organization, error)
+}
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{db}
+}
+
+func (r *organizationRepository) Create(organization *entity.Organization) error {
+	return r.db.Create(organization).Error
+}
+
+func (r *organizationRepository) FindByID(id string) (*entity.Organization, error) {
+	var organization entity.Organization
+	err := r.db.Where("id = ?", id).First(&organization).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("organization not found")
+		}
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (r *organizationRepository) FindByName(name string) (*entity.Organization, error) {
+	var organization entity.Organization
+	err := r.db.Where("name = ?", name).First(&organization).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("organization not found")
+		}
+		return nil, err
+	}
+	return &organization, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"event-ticketing/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	Create(message *entity.OutboxMessage) error
+	FindPending(now time.Time, limit int) ([]entity.OutboxMessage, error)
+	Claim(id uuid.UUID, previousAvailableAt, leaseUntil time.Time) (bool, error)
+	MarkPublished(id uuid.UUID, publishedAt time.Time) error
+	RecordFailure(id uuid.UUID, attempts int, availableAt time.Time, lastError string) error
+	DeletePublishedBefore(before time.Time) (int64, error)
+	WithTx(tx *gorm.DB) OutboxRepository
+	WithTenant(organizationID string) OutboxRepository
+}
+
+type outboxRepository struct {
+	db             *gorm.DB
+	organizationID uuid.UUID
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) WithTx(tx *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: tx, organizationID: r.organizationID}
+}
+
+func (r *outboxRepository) WithTenant(organizationID string) OutboxRepository {
+	return &outboxRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *outboxRepository) Create(message *entity.OutboxMessage) error {
+	message.OrganizationID = r.organizationID
+	return r.db.Create(message).Error
+}
+
+// FindPending mencari pesan yang belum terkirim lintas organisasi, urut sesuai waktu dibuat
+func (r *outboxRepository) FindPending(now time.Time, limit int) ([]entity.OutboxMessage, error) {
+	var messages []entity.OutboxMessage
+	err := r.db.Where("published_at IS NULL AND available_at <= ?", now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&messages).Error
+	return messages, err
+}
+
+// Claim menunda pesan sampai leaseUntil hanya jika belum diambil relay lain. Jika proses
+// mati sebelum pesan ditandai terkirim, pesan dikirim lagi setelah lease habis.
+func (r *outboxRepository) Claim(id uuid.UUID, previousAvailableAt, leaseUntil time.Time) (bool, error) {
+	result := r.db.Model(&entity.OutboxMessage{}).
+		Where("id = ? AND published_at IS NULL AND available_at = ?", id, previousAvailableAt).
+		Update("available_at", leaseUntil)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *outboxRepository) MarkPublished(id uuid.UUID, publishedAt time.Time) error {
+	return r.db.Model(&entity.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"published_at": publishedAt, "last_error": ""}).Error
+}
+
+func (r *outboxRepository) RecordFailure(id uuid.UUID, attempts int, availableAt time.Time, lastError string) error {
+	return r.db.Model(&entity.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"attempts": attempts, "available_at": availableAt, "last_error": lastError}).Error
+}
+
+// DeletePublishedBefore menghapus permanen pesan yang sudah terkirim sebelum waktu tertentu
+func (r *outboxRepository) DeletePublishedBefore(before time.Time) (int64, error) {
+	result := r.db.Unscoped().Where("published_at IS NOT NULL AND published_at < ?", before).Delete(&entity.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type QueueRepository interface {
+	Create(entry *entity.QueueEntry) error
+	FindByHash(eventID, hash string) (*entity.QueueEntry, error)
+	FindActiveByUser(eventID, userID string) (*entity.QueueEntry, error)
+	UpdateTokenHash(id, hash string) error
+	CountAhead(entry *entity.QueueEntry) (int64, error)
+	RequiresAdmission(eventID string) (bool, error)
+	ConsumeAdmission(eventID, userID, hash string) (bool, error)
+	FindWaitingEvents() ([]entity.Event, error)
+	AdmitNext(eventID string, limit int, expiresAt time.Time) (int64, error)
+	ExpireAdmissions() (int64, error)
+	WithTx(tx *gorm.DB) QueueRepository
+	WithTenant(organizationID string) QueueRepository
+}
+
+type queueRepository struct {
+	db             *gorm.DB
+	organizationID uuid.UUID
+}
+
+func NewQueueRepository(db *gorm.DB) QueueRepository {
+	return &queueRepository{db: db}
+}
+
+func (r *queueRepository) WithTx(tx *gorm.DB) QueueRepository {
+	return &queueRepository{db: tx, organizationID: r.organizationID}
+}
+
+func (r *queueRepository) WithTenant(organizationID string) QueueRepository {
+	return &queueRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *queueRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
+}
+
+func (r *queueRepository) Create(entry *entity.QueueEntry) error {
+	entry.OrganizationID = r.organizationID
+	return r.db.Create(entry).Error
+}
+
+func (r *queueRepository) FindByHash(eventID, hash string) (*entity.QueueEntry, error) {
+	var entry entity.QueueEntry
+	err := r.scoped().Where("event_id = ? AND token_hash = ?", eventID, hash).First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("queue entry not found")
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindActiveByUser mencari entri user yang masih menunggu atau sudah diizinkan masuk
+func (r *queueRepository) FindActiveByUser(eventID, userID string) (*entity.QueueEntry, error) {
+	var entry entity.QueueEntry
+	err := r.scoped().
+		Where("event_id = ? AND user_id = ? AND status IN ?", eventID, userID, []entity.QueueEntryStatus{entity.QueueWaiting, entity.QueueAdmitted}).
+		First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("queue entry not found")
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *queueRepository) UpdateTokenHash(id, hash string) error {
+	return r.scoped().Model(&entity.QueueEntry{}).Where("id = ?", id).Update("token_hash", hash).Error
+}
+
+// CountAhead menghitung entri yang masih menunggu dan bergabung lebih dulu
+func (r *queueRepository) CountAhead(entry *entity.QueueEntry) (int64, error) {
+	var count int64
+	err := r.scoped().Model(&entity.QueueEntry{}).
+		Where("event_id = ? AND status = ? AND id < ?", entry.EventID, entity.QueueWaiting, entry.ID).
+		Count(&count).Error
+	return count, err
+}
+
+// RequiresAdmission membaca flag antrean event tanpa lock dan tanpa menghitung tiket terjual
+func (r *queueRepository) RequiresAdmission(eventID string) (bool, error) {
+	var event entity.Event
+	err := r.scoped().Select("queue_enabled").Where("id = ?", eventID).First(&event).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, errors.New("event not found")
+		}
+		return false, err
+	}
+	return event.QueueEnabled, nil
+}
+
+// ConsumeAdmission menandai izin masuk sudah dipakai secara atomik sehingga
+// satu izin hanya bisa dipakai untuk satu pembelian
+func (r *queueRepository) ConsumeAdmission(eventID, userID, hash string) (bool, error) {
+	result := r.scoped().Model(&entity.QueueEntry{}).
+		Where("event_id = ? AND user_id = ? AND token_hash = ? AND status = ? AND expires_at > ?",
+			eventID, userID, hash, entity.QueueAdmitted, time.Now()).
+		Update("status", entity.QueueUsed)
+	return result.RowsAffected == 1, result.Error
+}
+
+// FindWaitingEvents mengembalikan event lintas organisasi yang antreannya masih berisi user menunggu
+func (r *queueRepository) FindWaitingEvents() ([]entity.Event, error) {
+	var events []entity.Event
+	err := r.db.Where("queue_enabled = ? AND status = ?", true, entity.ActiveEvent).
+		Where("EXISTS (SELECT 1 FROM queue_entries WHERE queue_entries.event_id = events.id AND queue_entries.status = ? AND queue_entries.deleted_at IS NULL)", entity.QueueWaiting).
+		Find(&events).Error
+	return events, err
+}
+
+// AdmitNext mengizinkan masuk sejumlah user terdepan pada antrean sebuah event
+func (r *queueRepository) AdmitNext(eventID string, limit int, expiresAt time.Time) (int64, error) {
+	var ids []uuid.UUID
+	err := r.db.Model(&entity.QueueEntry{}).
+		Where("event_id = ? AND status = ?", eventID, entity.QueueWaiting).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	result := r.db.Model(&entity.QueueEntry{}).
+		Where("id IN ? AND status = ?", ids, entity.QueueWaiting).
+		Updates(map[string]interface{}{
+			"status":      entity.QueueAdmitted,
+			"admitted_at": time.Now(),
+			"expires_at":  expiresAt,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// ExpireAdmissions menutup izin masuk lintas organisasi yang tidak dipakai sampai batas waktunya
+func (r *queueRepository) ExpireAdmissions() (int64, error) {
+	result := r.db.Model(&entity.QueueEntry{}).
+		Where("status = ? AND expires_at <= ?", entity.QueueAdmitted, time.Now()).
+		Update("status", entity.QueueExpired)
+	return result.RowsAffected, result.Error
+}
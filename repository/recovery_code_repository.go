@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"event-ticketing/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type RecoveryCodeRepository interface {
+	ReplaceForUser(userID uuid.UUID, hashes []string) error
+	Use(userID string, hash string) (bool, error)
+	DeleteByUserID(userID string) error
+	WithTenant(organizationID string) RecoveryCodeRepository
+}
+
+type recoveryCodeRepository struct {
+	db             *gorm.DB
+	organizationID uuid.UUID
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: db}
+}
+
+func (r *recoveryCodeRepository) WithTenant(organizationID string) RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *recoveryCodeRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
+}
+
+// ReplaceForUser menghapus recovery code lama dan menyimpan yang baru dalam satu transaksi
+func (r *recoveryCodeRepository) ReplaceForUser(userID uuid.UUID, hashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Scopes(tenantScope(r.organizationID)).
+			Where("user_id = ?", userID).
+			Delete(&entity.RecoveryCode{}).Error
+		if err != nil {
+			return err
+		}
+
+		codes := make([]entity.RecoveryCode, len(hashes))
+		for i, hash := range hashes {
+			codes[i] = entity.RecoveryCode{OrganizationID: r.organizationID, UserID: userID, CodeHash: hash}
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *recoveryCodeRepository) Use(userID string, hash string) (bool, error) {
+	result := r.scoped().Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Limit(1).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *recoveryCodeRepository) DeleteByUserID(userID string) error {
+	return r.scoped().Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error
+}
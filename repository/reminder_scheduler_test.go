@@ -0,0 +1,83 @@
+package repository_test
+
+import (
+	"errors"
+	"event-ticketing/config"
+	"event-ticketing/entity"
+	"event-ticketing/notification"
+	"event-ticketing/repository"
+	"event-ticketing/service"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// failingNotifier gagal menyimpan pengingat, misalnya karena database bermasalah
+type failingNotifier struct {
+	notification.Notifier
+}
+
+func (n *failingNotifier) WithTx(tx *gorm.DB) notification.Notifier {
+	return n
+}
+
+func (n *failingNotifier) EventReminder(ticket *entity.Ticket) error {
+	return errors.New("failed to store notification")
+}
+
+func TestReminderSchedulerClaimsReminderWithNotification(t *testing.T) {
+	db := repository.OpenTestDB(t)
+	organization := repository.SeedOrganization(t, db)
+	user := repository.SeedUser(t, db, organization.ID)
+	event := repository.SeedEvent(t, db, user, 10, 100)
+	ticket := repository.SeedTicket(t, db, event, user.ID, entity.PurchasedTicket)
+	if err := db.Model(ticket).Update("purchase_date", time.Now().AddDate(0, 0, -30)).Error; err != nil {
+		t.Fatalf("failed to backdate ticket: %v", err)
+	}
+
+	// Event dimulai 7 hari lagi, jadi pengingat 8 hari sebelumnya sudah jatuh tempo
+	cfg := config.Config{ReminderOffsets: []time.Duration{8 * 24 * time.Hour}}
+	reminderRepo := repository.NewTicketReminderRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+
+	err := service.NewReminderScheduler(db, reminderRepo, &failingNotifier{}, cfg).RunDue()
+	if err == nil {
+		t.Fatal("expected RunDue to fail when the notification cannot be stored")
+	}
+	if reminders := countReminders(t, db, ticket); reminders != 0 {
+		t.Fatalf("reminder was claimed without a notification: %d reminders", reminders)
+	}
+
+	notifier := notification.NewNotifier(notificationRepo, repository.NewUserRepository(db), repository.NewTicketRepository(db), nil, cfg)
+	if err := service.NewReminderScheduler(db, reminderRepo, notifier, cfg).RunDue(); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.NewReminderScheduler(db, reminderRepo, notifier, cfg).RunDue(); err != nil {
+		t.Fatal(err)
+	}
+
+	if reminders := countReminders(t, db, ticket); reminders != 1 {
+		t.Fatalf("got %d reminders, want 1", reminders)
+	}
+	var notifications int64
+	err = db.Model(&entity.Notification{}).
+		Where("user_id = ? AND type = ? AND status = ?", user.ID, entity.EventReminderNotification, entity.PendingNotification).
+		Count(&notifications).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notifications != 1 {
+		t.Fatalf("got %d pending reminder notifications, want 1", notifications)
+	}
+}
+
+func countReminders(t *testing.T, db *gorm.DB, ticket *entity.Ticket) int64 {
+	t.Helper()
+
+	var count int64
+	if err := db.Model(&entity.TicketReminder{}).Where("ticket_id = ?", ticket.ID).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
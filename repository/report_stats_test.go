@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"event-ticketing/entity"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+// reportFixture berisi organisasi dengan beberapa event untuk menguji agregat laporan
+type reportFixture struct {
+	organization *entity.Organization
+	upcoming     *entity.Event
+	started      *entity.Event
+	empty        *entity.Event
+}
+
+// seedReportFixture membuat organisasi dengan tiga event aktif:
+//   - upcoming (kapasitas 100, harga 100): 3 terjual (1 check-in), 2 dibatalkan
+//   - started, sudah dimulai (kapasitas 50, harga 200): 2 terjual (1 check-in), 1 dibatalkan
+//   - empty (kapasitas 10) tanpa tiket
+//
+// Event yang dihapus dan tiket milik organisasi lain juga dibuat dan tidak boleh ikut dihitung.
+func seedReportFixture(t testing.TB, db *gorm.DB) reportFixture {
+	t.Helper()
+
+	organization := seedOrganization(t, db)
+	user := seedUser(t, db, organization.ID)
+	ticketRepo := NewTicketRepository(db).WithTenant(organization.ID.String())
+
+	upcoming := seedEvent(t, db, user, 100, 100)
+	checkedIn := seedTicket(t, db, upcoming, user.ID, entity.PurchasedTicket)
+	seedTicket(t, db, upcoming, user.ID, entity.PurchasedTicket)
+	seedTicket(t, db, upcoming, user.ID, entity.PurchasedTicket)
+	seedTicket(t, db, upcoming, user.ID, entity.CancelledTicket)
+	seedTicket(t, db, upcoming, user.ID, entity.CancelledTicket)
+	checkIn(t, ticketRepo, checkedIn)
+
+	started := seedEvent(t, db, user, 50, 200)
+	checkedIn = seedTicket(t, db, started, user.ID, entity.PurchasedTicket)
+	seedTicket(t, db, started, user.ID, entity.PurchasedTicket)
+	seedTicket(t, db, started, user.ID, entity.CancelledTicket)
+	checkIn(t, ticketRepo, checkedIn)
+	if err := db.Model(started).Update("start_date", time.Now().Add(-time.Hour)).Error; err != nil {
+		t.Fatalf("failed to start event: %v", err)
+	}
+
+	empty := seedEvent(t, db, user, 10, 100)
+
+	deleted := seedEvent(t, db, user, 1000, 100)
+	seedTicket(t, db, deleted, user.ID, entity.PurchasedTicket)
+	// Repository menolak menghapus event yang sudah punya tiket terjual, jadi hapus langsung
+	if err := db.Delete(deleted).Error; err != nil {
+		t.Fatalf("failed to delete event: %v", err)
+	}
+
+	other := seedOrganization(t, db)
+	otherUser := seedUser(t, db, other.ID)
+	otherEvent := seedEvent(t, db, otherUser, 500, 100)
+	seedTicket(t, db, otherEvent, otherUser.ID, entity.PurchasedTicket)
+	seedTicket(t, db, otherEvent, otherUser.ID, entity.CancelledTicket)
+
+	return reportFixture{organization: organization, upcoming: upcoming, started: started, empty: empty}
+}
+
+func checkIn(t testing.TB, repo TicketRepository, ticket *entity.Ticket) {
+	t.Helper()
+
+	ok, err := repo.CheckIn(ticket.ID.String(), "A", time.Now())
+	if err != nil || !ok {
+		t.Fatalf("failed to check in ticket: ok=%v err=%v", ok, err)
+	}
+}
+
+func TestGetStatsByEvent(t *testing.T) {
+	db := openTestDB(t)
+	fixture := seedReportFixture(t, db)
+	repo := NewTicketRepository(db).WithTenant(fixture.organization.ID.String())
+
+	stats, err := repo.GetStatsByEvent()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[uuid.UUID]EventTicketStats{
+		fixture.upcoming.ID: {EventID: fixture.upcoming.ID, Sold: 3, Cancelled: 2, Revenue: 300, CheckedIn: 1, NoShows: 0},
+		fixture.started.ID:  {EventID: fixture.started.ID, Sold: 2, Cancelled: 1, Revenue: 400, CheckedIn: 1, NoShows: 1},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("got stats for %d events, want %d: %+v", len(stats), len(want), stats)
+	}
+	for _, stat := range stats {
+		if stat != want[stat.EventID] {
+			t.Errorf("stats for event %s = %+v, want %+v", stat.EventID, stat, want[stat.EventID])
+		}
+	}
+
+	stats, err = repo.GetStatsByEvent(fixture.started.ID.String(), fixture.empty.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 1 || stats[0] != want[fixture.started.ID] {
+		t.Fatalf("filtered stats = %+v, want only %+v", stats, want[fixture.started.ID])
+	}
+}
+
+func TestGetCapacitySummary(t *testing.T) {
+	db := openTestDB(t)
+	fixture := seedReportFixture(t, db)
+	repo := NewEventRepository(db).WithTenant(fixture.organization.ID.String())
+
+	events, capacity, err := repo.GetCapacitySummary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events != 3 || capacity != 160 {
+		t.Fatalf("GetCapacitySummary() = %d events, %d capacity, want 3 events, 160 capacity", events, capacity)
+	}
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"event-ticketing/utils"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type ReportSubscriptionRepository interface {
+	Create(subscription *entity.ReportSubscription) error
+	FindByID(id string) (*entity.ReportSubscription, error)
+	FindAll(params utils.PaginationParams) ([]entity.ReportSubscription, int64, error)
+	Delete(id string) error
+	FindDue(now time.Time, limit int) ([]entity.ReportSubscription, error)
+	Claim(id uuid.UUID, previousRunAt, nextRunAt time.Time) (bool, error)
+	RecordRun(id uuid.UUID, ranAt time.Time, lastError string) error
+	WithTenant(organizationID string) ReportSubscriptionRepository
+}
+
+type reportSubscriptionRepository struct {
+	db             *gorm.DB
+	organizationID uuid.UUID
+}
+
+func NewReportSubscriptionRepository(db *gorm.DB) ReportSubscriptionRepository {
+	return &reportSubscriptionRepository{db: db}
+}
+
+func (r *reportSubscriptionRepository) WithTenant(organizationID string) ReportSubscriptionRepository {
+	return &reportSubscriptionRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *reportSubscriptionRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
+}
+
+func (r *reportSubscriptionRepository) Create(subscription *entity.ReportSubscription) error {
+	subscription.OrganizationID = r.organizationID
+	return r.db.Create(subscription).Error
+}
+
+func (r *reportSubscriptionRepository) FindByID(id string) (*entity.ReportSubscription, error) {
+	var subscription entity.ReportSubscription
+	err := r.scoped().Where("id = ?", id).First(&subscription).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("report subscription not found")
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *reportSubscriptionRepository) FindAll(params utils.PaginationParams) ([]entity.ReportSubscription, int64, error) {
+	var subscriptions []entity.ReportSubscription
+	var count int64
+
+	if err := r.scoped().Model(&entity.ReportSubscription{}).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.scoped().Order("created_at DESC").Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&subscriptions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return subscriptions, count, nil
+}
+
+func (r *reportSubscriptionRepository) Delete(id string) error {
+	return r.scoped().Where("id = ?", id).Delete(&entity.ReportSubscription{}).Error
+}
+
+// FindDue mencari langganan yang jadwalnya sudah lewat, lintas organisasi
+func (r *reportSubscriptionRepository) FindDue(now time.Time, limit int) ([]entity.ReportSubscription, error) {
+	var subscriptions []entity.ReportSubscription
+	err := r.db.Where("next_run_at <= ?", now).Order("next_run_at ASC").Limit(limit).Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// Claim memajukan next_run_at hanya jika nilainya belum diubah proses lain, sehingga
+// setiap jadwal dikirim satu kali walaupun beberapa instance berjalan bersamaan
+func (r *reportSubscriptionRepository) Claim(id uuid.UUID, previousRunAt, nextRunAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.ReportSubscription{}).
+		Where("id = ? AND next_run_at = ?", id, previousRunAt).
+		Update("next_run_at", nextRunAt)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *reportSubscriptionRepository) RecordRun(id uuid.UUID, ranAt time.Time, lastError string) error {
+	return r.db.Model(&entity.ReportSubscription{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_run_at": ranAt, "last_error": lastError}).Error
+}
@@ -0,0 +1,33 @@
+package repository_test
+
+import (
+	"event-ticketing/repository"
+	"event-ticketing/service"
+	"testing"
+)
+
+func TestGenerateSummaryReportTotals(t *testing.T) {
+	db := repository.OpenTestDB(t)
+	fixture := repository.SeedReportFixture(t, db)
+
+	reportService := service.NewReportService(
+		repository.NewEventRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewTicketRepository(db),
+	).WithTenant(fixture.OrganizationID())
+
+	report, err := reportService.GenerateSummaryReport()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.TotalEvents != 3 || report.TotalTickets != 160 {
+		t.Errorf("events/capacity = %d/%d, want 3/160", report.TotalEvents, report.TotalTickets)
+	}
+	if report.TotalSold != 5 || report.TotalCancelled != 3 || report.TotalRevenue != 700 {
+		t.Errorf("sold/cancelled/revenue = %d/%d/%v, want 5/3/700", report.TotalSold, report.TotalCancelled, report.TotalRevenue)
+	}
+	if report.TotalCheckedIn != 2 || report.TotalNoShows != 1 || report.CheckInRate != 0.4 {
+		t.Errorf("checked in/no-shows/rate = %d/%d/%v, want 2/1/0.4", report.TotalCheckedIn, report.TotalNoShows, report.CheckInRate)
+	}
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"event-ticketing/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SigningKeyRepository interface {
+	Create(key *entity.SigningKey) error
+	FindValid() ([]entity.SigningKey, error)
+	RetireAllExcept(id string, expiresAt time.Time) error
+	DeleteExpired() error
+}
+
+type signingKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyRepository(db *gorm.DB) SigningKeyRepository {
+	return &signingKeyRepository{db}
+}
+
+func (r *signingKeyRepository) Create(key *entity.SigningKey) error {
+	return r.db.Create(key).Error
+}
+
+// FindValid mengembalikan key yang masih bisa dipakai untuk verifikasi, dari yang terbaru
+func (r *signingKeyRepository) FindValid() ([]entity.SigningKey, error) {
+	var keys []entity.SigningKey
+	err := r.db.Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+func (r *signingKeyRepository) RetireAllExcept(id string, expiresAt time.Time) error {
+	return r.db.Model(&entity.SigningKey{}).
+		Where("id <> ? AND retired_at IS NULL", id).
+		Updates(map[string]interface{}{"retired_at": time.Now(), "expires_at": expiresAt}).Error
+}
+
+func (r *signingKeyRepository) DeleteExpired() error {
+	return r.db.Unscoped().Where("expires_at <= ?", time.Now()).Delete(&entity.SigningKey{}).Error
+}
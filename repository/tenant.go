@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+// tenantScope membatasi query hanya pada data milik organisasi tertentu.
+// ID organisasi yang kosong atau tidak valid tidak akan cocok dengan data apa pun.
+func tenantScope(organizationID uuid.UUID) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("organization_id = ?", organizationID)
+	}
+}
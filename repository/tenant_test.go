@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"event-ticketing/entity"
+	"event-ticketing/utils"
+	"testing"
+)
+
+func TestTenantIsolation(t *testing.T) {
+	db := openTestDB(t)
+
+	orgA := seedOrganization(t, db)
+	orgB := seedOrganization(t, db)
+
+	userA := seedUser(t, db, orgA.ID)
+	eventA := seedEvent(t, db, userA, 100, 50000)
+	ticketA := seedTicket(t, db, eventA, userA.ID, entity.PurchasedTicket)
+
+	userB := seedUser(t, db, orgB.ID)
+	seedTicket(t, db, seedEvent(t, db, userB, 100, 50000), userB.ID, entity.PurchasedTicket)
+
+	params := utils.PaginationParams{Page: 1, Limit: 100}
+	eventRepoB := NewEventRepository(db).WithTenant(orgB.ID.String())
+	ticketRepoB := NewTicketRepository(db).WithTenant(orgB.ID.String())
+	userRepoB := NewUserRepository(db).WithTenant(orgB.ID.String())
+
+	t.Run("own organization", func(t *testing.T) {
+		if _, err := NewEventRepository(db).WithTenant(orgA.ID.String()).FindByID(eventA.ID.String()); err != nil {
+			t.Fatalf("expected event to be found in its own organization, got %v", err)
+		}
+		if _, err := NewTicketRepository(db).WithTenant(orgA.ID.String()).FindByID(ticketA.ID.String()); err != nil {
+			t.Fatalf("expected ticket to be found in its own organization, got %v", err)
+		}
+	})
+
+	t.Run("event FindByID", func(t *testing.T) {
+		if _, err := eventRepoB.FindByID(eventA.ID.String()); err == nil || err.Error() != "event not found" {
+			t.Fatalf("expected event not found, got %v", err)
+		}
+	})
+
+	t.Run("event FindAll", func(t *testing.T) {
+		events, _, err := eventRepoB.FindAll(params, eventA.Name, "", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 0 {
+			t.Fatalf("expected no events from another organization, got %d", len(events))
+		}
+	})
+
+	t.Run("ticket FindByID", func(t *testing.T) {
+		if _, err := ticketRepoB.FindByID(ticketA.ID.String()); err == nil || err.Error() != "ticket not found" {
+			t.Fatalf("expected ticket not found, got %v", err)
+		}
+		if _, err := ticketRepoB.FindByIDWithoutEvent(ticketA.ID.String()); err == nil || err.Error() != "ticket not found" {
+			t.Fatalf("expected ticket not found, got %v", err)
+		}
+	})
+
+	t.Run("ticket FindByBookingCode", func(t *testing.T) {
+		if _, err := ticketRepoB.FindByBookingCode(ticketA.BookingCode); err == nil || err.Error() != "ticket not found" {
+			t.Fatalf("expected ticket not found, got %v", err)
+		}
+	})
+
+	t.Run("ticket FindByEventID", func(t *testing.T) {
+		tickets, count, err := ticketRepoB.FindByEventID(eventA.ID.String(), params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 || len(tickets) != 0 {
+			t.Fatalf("expected no tickets from another organization, got %d", count)
+		}
+	})
+
+	t.Run("ticket FindByUserID", func(t *testing.T) {
+		tickets, count, err := ticketRepoB.FindByUserID(userA.ID.String(), params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 || len(tickets) != 0 {
+			t.Fatalf("expected no tickets from another organization, got %d", count)
+		}
+	})
+
+	t.Run("ticket FindAll", func(t *testing.T) {
+		tickets, _, err := ticketRepoB.FindAll(params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ticket := range tickets {
+			if ticket.OrganizationID != orgB.ID {
+				t.Fatalf("ticket %s from organization %s leaked into %s", ticket.ID, ticket.OrganizationID, orgB.ID)
+			}
+		}
+	})
+
+	t.Run("ticket Update", func(t *testing.T) {
+		ticketA.Status = entity.CancelledTicket
+		if err := ticketRepoB.Update(ticketA); err == nil || err.Error() != "ticket not found" {
+			t.Fatalf("expected ticket not found, got %v", err)
+		}
+		updated, err := ticketRepoB.UpdateStatus(ticketA.ID.String(), entity.PurchasedTicket, entity.CancelledTicket)
+		if err != nil || updated {
+			t.Fatalf("expected cross-tenant status update to be a no-op, got updated=%v err=%v", updated, err)
+		}
+	})
+
+	t.Run("user FindByID", func(t *testing.T) {
+		if _, err := userRepoB.FindByID(userA.ID.String()); err == nil || err.Error() != "user not found" {
+			t.Fatalf("expected user not found, got %v", err)
+		}
+		if _, err := userRepoB.FindByEmail(userA.Email); err == nil || err.Error() != "user not found" {
+			t.Fatalf("expected user not found, got %v", err)
+		}
+	})
+}
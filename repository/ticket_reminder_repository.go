@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"event-ticketing/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TicketReminderRepository interface {
+	FindDue(offset, nextOffset time.Duration, now time.Time, limit int) ([]entity.Ticket, error)
+	Claim(ticket *entity.Ticket, offset time.Duration) (bool, error)
+	WithTx(tx *gorm.DB) TicketReminderRepository
+}
+
+type ticketReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewTicketReminderRepository(db *gorm.DB) TicketReminderRepository {
+	return &ticketReminderRepository{db: db}
+}
+
+func (r *ticketReminderRepository) WithTx(tx *gorm.DB) TicketReminderRepository {
+	return &ticketReminderRepository{db: tx}
+}
+
+// FindDue mencari tiket terjual lintas organisasi yang event-nya dimulai dalam rentang
+// (now+nextOffset, now+offset] dan belum menerima pengingat untuk offset tersebut.
+// Tiket yang dibeli setelah waktu pengingat lewat dan user yang menolak pengingat dilewati.
+func (r *ticketReminderRepository) FindDue(offset, nextOffset time.Duration, now time.Time, limit int) ([]entity.Ticket, error) {
+	offsetMinutes := int(offset / time.Minute)
+
+	var tickets []entity.Ticket
+	err := r.db.Preload("Event").
+		Joins("JOIN events ON events.id = tickets.event_id AND events.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = tickets.user_id AND users.deleted_at IS NULL").
+		Joins("LEFT JOIN ticket_reminders ON ticket_reminders.ticket_id = tickets.id AND ticket_reminders.offset_minutes = ?", offsetMinutes).
+		Where("tickets.status = ?", entity.PurchasedTicket).
+		Where("events.start_date > ? AND events.start_date <= ?", now.Add(nextOffset), now.Add(offset)).
+		Where("tickets.purchase_date < events.start_date - INTERVAL ? MINUTE", offsetMinutes).
+		Where("users.event_reminders_opt_out = ?", false).
+		Where("ticket_reminders.id IS NULL").
+		Order("events.start_date ASC").
+		Limit(limit).
+		Find(&tickets).Error
+	return tickets, err
+}
+
+// Claim mencatat pengingat dan mengembalikan false jika pengingat untuk tiket dan offset
+// ini sudah dicatat proses lain. Jalankan di transaksi yang sama dengan pembuatan
+// notifikasinya agar pengingat tidak tercatat tanpa email.
+func (r *ticketReminderRepository) Claim(ticket *entity.Ticket, offset time.Duration) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.TicketReminder{
+		OrganizationID: ticket.OrganizationID,
+		TicketID:       ticket.ID,
+		OffsetMinutes:  int(offset / time.Minute),
+	})
+	return result.RowsAffected == 1, result.Error
+}
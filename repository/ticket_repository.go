@@ -4,7 +4,9 @@ import (
 	"errors"
 	"event-ticketing/entity"
 	"event-ticketing/utils"
+	"time"
 
+	"github.com/gofrs/uuid/v5"
 	"gorm.io/gorm"
 )
 
@@ -12,32 +14,88 @@ type TicketRepository interface {
 	Create(ticket *entity.Ticket) error
 	FindByID(id string) (*entity.Ticket, error)
 	FindByIDWithoutEvent(id string) (*entity.Ticket, error)
+	FindByBookingCode(bookingCode string) (*entity.Ticket, error)
 	FindAll(params utils.PaginationParams) ([]entity.Ticket, int64, error)
 	FindByUserID(userID string, params utils.PaginationParams) ([]entity.Ticket, int64, error)
+	FindAllByUserID(userID string) ([]entity.Ticket, error)
 	FindByEventID(eventID string, params utils.PaginationParams) ([]entity.Ticket, int64, error)
 	Update(ticket *entity.Ticket) error
+	UpdateStatus(id string, from, to entity.TicketStatus) (bool, error)
 	Delete(id string) error
 	CountByEventID(eventID string) (int, error)
 	CountByEventAndStatus(eventID string, status entity.TicketStatus) (int, error)
+	CountActiveByUserAndEvent(userID, eventID string) (int, error)
 	GetRevenue(eventID string) (float64, error)
+	GetStatsByEvent(eventIDs ...string) ([]EventTicketStats, error)
+	FindSalesByEventID(eventID string) ([]TicketSale, error)
+	EachAttendeeByEventID(eventID string, fn func(attendee Attendee) error) error
+	CheckIn(id, gate string, at time.Time) (bool, error)
+	FindCheckInTimesByEventID(eventID string) ([]time.Time, error)
+	CountCheckInsByGate(eventID string) ([]GateCount, error)
+	FindNoShowsByEventID(eventID string) ([]Attendee, error)
+	FindHolderIDsByEventID(eventID string) ([]uuid.UUID, error)
 	WithTx(tx *gorm.DB) TicketRepository
+	WithTenant(organizationID string) TicketRepository
+}
+
+// EventTicketStats berisi agregat tiket satu event
+type EventTicketStats struct {
+	EventID   uuid.UUID
+	Sold      int
+	Cancelled int
+	Revenue   float64
+	CheckedIn int
+	NoShows   int
+}
+
+// GateCount adalah jumlah check-in pada satu gate
+type GateCount struct {
+	Gate  string `json:"gate"`
+	Count int    `json:"count"`
+}
+
+// TicketSale adalah waktu penjualan dan pembatalan satu tiket untuk laporan penjualan
+type TicketSale struct {
+	PurchaseDate time.Time
+	CancelledAt  *time.Time
+	Price        float64
+}
+
+// Attendee adalah satu baris daftar peserta event beserta nama dan email pembeli
+type Attendee struct {
+	TicketID     uuid.UUID
+	BookingCode  string
+	Status       entity.TicketStatus
+	Price        float64
+	PurchaseDate time.Time
+	CancelledAt  *time.Time
+	CheckedInAt  *time.Time
+	CheckInGate  string
+	UserName     string
+	UserEmail    string
 }
 
 type ticketRepository struct {
-	db *gorm.DB
+	db             *gorm.DB
+	organizationID uuid.UUID
 }
 
 func NewTicketRepository(db *gorm.DB) TicketRepository {
-	return &ticketRepository{db}
+	return &ticketRepository{db: db}
+}
+
+func (r *ticketRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
 }
 
 func (r *ticketRepository) Create(ticket *entity.Ticket) error {
+	ticket.OrganizationID = r.organizationID
 	return r.db.Create(ticket).Error
 }
 
 func (r *ticketRepository) FindByID(id string) (*entity.Ticket, error) {
 	var ticket entity.Ticket
-	err := r.db.Preload("Event").Where("id = ?", id).First(&ticket).Error
+	err := r.scoped().Preload("Event").Where("id = ?", id).First(&ticket).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("ticket not found")
@@ -49,7 +107,19 @@ func (r *ticketRepository) FindByID(id string) (*entity.Ticket, error) {
 
 func (r *ticketRepository) FindByIDWithoutEvent(id string) (*entity.Ticket, error) {
 	var ticket entity.Ticket
-	err := r.db.Where("id = ?", id).First(&ticket).Error
+	err := r.scoped().Where("id = ?", id).First(&ticket).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("ticket not found")
+		}
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+func (r *ticketRepository) FindByBookingCode(bookingCode string) (*entity.Ticket, error) {
+	var ticket entity.Ticket
+	err := r.scoped().Preload("Event").Where("booking_code = ?", bookingCode).First(&ticket).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("ticket not found")
@@ -60,18 +130,22 @@ func (r *ticketRepository) FindByIDWithoutEvent(id string) (*entity.Ticket, erro
 }
 
 func (r *ticketRepository) WithTx(tx *gorm.DB) TicketRepository {
-	return &ticketRepository{db: tx}
+	return &ticketRepository{db: tx, organizationID: r.organizationID}
+}
+
+func (r *ticketRepository) WithTenant(organizationID string) TicketRepository {
+	return &ticketRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
 }
 
 func (r *ticketRepository) FindAll(params utils.PaginationParams) ([]entity.Ticket, int64, error) {
 	var tickets []entity.Ticket
 	var count int64
 
-	if err := r.db.Model(&entity.Ticket{}).Count(&count).Error; err != nil {
+	if err := r.scoped().Model(&entity.Ticket{}).Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := r.db.Preload("Event").Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&tickets).Error; err != nil {
+	if err := r.scoped().Preload("Event").Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&tickets).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -82,26 +156,35 @@ func (r *ticketRepository) FindByUserID(userID string, params utils.PaginationPa
 	var tickets []entity.Ticket
 	var count int64
 
-	if err := r.db.Model(&entity.Ticket{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+	if err := r.scoped().Model(&entity.Ticket{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := r.db.Preload("Event").Where("user_id = ?", userID).Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&tickets).Error; err != nil {
+	if err := r.scoped().Preload("Event").Where("user_id = ?", userID).Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&tickets).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return tickets, count, nil
 }
 
+// FindAllByUserID mengambil seluruh tiket user tanpa paginasi, termasuk yang sudah dihapus
+func (r *ticketRepository) FindAllByUserID(userID string) ([]entity.Ticket, error) {
+	var tickets []entity.Ticket
+	err := r.scoped().Unscoped().Preload("Event", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("user_id = ?", userID).Order("purchase_date").Find(&tickets).Error
+	return tickets, err
+}
+
 func (r *ticketRepository) FindByEventID(eventID string, params utils.PaginationParams) ([]entity.Ticket, int64, error) {
 	var tickets []entity.Ticket
 	var count int64
 
-	if err := r.db.Model(&entity.Ticket{}).Where("event_id = ?", eventID).Count(&count).Error; err != nil {
+	if err := r.scoped().Model(&entity.Ticket{}).Where("event_id = ?", eventID).Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := r.db.Preload("Event").Where("event_id = ?", eventID).Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&tickets).Error; err != nil {
+	if err := r.scoped().Preload("Event").Where("event_id = ?", eventID).Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&tickets).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -109,27 +192,173 @@ func (r *ticketRepository) FindByEventID(eventID string, params utils.Pagination
 }
 
 func (r *ticketRepository) Update(ticket *entity.Ticket) error {
+	if ticket.OrganizationID != r.organizationID {
+		return errors.New("ticket not found")
+	}
 	return r.db.Save(ticket).Error
 }
 
 func (r *ticketRepository) Delete(id string) error {
-	return r.db.Delete(&entity.Ticket{}, id).Error
+	return r.scoped().Where("id = ?", id).Delete(&entity.Ticket{}).Error
 }
 
 func (r *ticketRepository) CountByEventID(eventID string) (int, error) {
 	var count int64
-	err := r.db.Model(&entity.Ticket{}).Where("event_id = ?", eventID).Count(&count).Error
+	err := r.scoped().Model(&entity.Ticket{}).Where("event_id = ?", eventID).Count(&count).Error
 	return int(count), err
 }
 
 func (r *ticketRepository) CountByEventAndStatus(eventID string, status entity.TicketStatus) (int, error) {
 	var count int64
-	err := r.db.Model(&entity.Ticket{}).Where("event_id = ? AND status = ?", eventID, status).Count(&count).Error
+	err := r.scoped().Model(&entity.Ticket{}).Where("event_id = ? AND status = ?", eventID, status).Count(&count).Error
 	return int(count), err
 }
 
 func (r *ticketRepository) GetRevenue(eventID string) (float64, error) {
 	var revenue float64
-	err := r.db.Model(&entity.Ticket{}).Select("COALESCE(SUM(price), 0)").Where("event_id = ? AND status = ?", eventID, entity.PurchasedTicket).Scan(&revenue).Error
+	err := r.scoped().Model(&entity.Ticket{}).Select("COALESCE(SUM(price), 0)").Where("event_id = ? AND status = ?", eventID, entity.PurchasedTicket).Scan(&revenue).Error
 	return revenue, err
 }
+
+// CountActiveByUserAndEvent menghitung tiket user pada sebuah event yang belum dibatalkan
+func (r *ticketRepository) CountActiveByUserAndEvent(userID, eventID string) (int, error) {
+	var count int64
+	err := r.scoped().Model(&entity.Ticket{}).
+		Where("user_id = ? AND event_id = ? AND status <> ?", userID, eventID, entity.CancelledTicket).
+		Count(&count).Error
+	return int(count), err
+}
+
+// UpdateStatus mengubah status tiket hanya jika statusnya masih from, sehingga
+// dua pembatalan paralel tidak mengurangi counter event dua kali
+func (r *ticketRepository) UpdateStatus(id string, from, to entity.TicketStatus) (bool, error) {
+	updates := map[string]interface{}{"status": to}
+	if to == entity.CancelledTicket {
+		updates["cancelled_at"] = time.Now()
+	}
+
+	result := r.scoped().Model(&entity.Ticket{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	return result.RowsAffected == 1, result.Error
+}
+
+// GetStatsByEvent menghitung tiket terjual, dibatalkan, pendapatan, check-in, dan no-show per
+// event dalam satu query GROUP BY. Tanpa eventIDs semua event organisasi yang belum dihapus
+// ikut dihitung. No-show hanya dihitung untuk event yang sudah dimulai.
+func (r *ticketRepository) GetStatsByEvent(eventIDs ...string) ([]EventTicketStats, error) {
+	var stats []EventTicketStats
+	query := r.db.Model(&entity.Ticket{}).
+		Select(`tickets.event_id,
+			SUM(CASE WHEN tickets.status = ? THEN 1 ELSE 0 END) AS sold,
+			SUM(CASE WHEN tickets.status = ? THEN 1 ELSE 0 END) AS cancelled,
+			COALESCE(SUM(CASE WHEN tickets.status = ? THEN tickets.price ELSE 0 END), 0) AS revenue,
+			SUM(CASE WHEN tickets.status = ? AND tickets.checked_in_at IS NOT NULL THEN 1 ELSE 0 END) AS checked_in,
+			SUM(CASE WHEN tickets.status = ? AND tickets.checked_in_at IS NULL AND events.start_date <= ? THEN 1 ELSE 0 END) AS no_shows`,
+			entity.PurchasedTicket, entity.CancelledTicket, entity.PurchasedTicket,
+			entity.PurchasedTicket, entity.PurchasedTicket, time.Now()).
+		Joins("JOIN events ON events.id = tickets.event_id AND events.deleted_at IS NULL").
+		Where("tickets.organization_id = ?", r.organizationID).
+		Group("tickets.event_id")
+
+	if len(eventIDs) > 0 {
+		query = query.Where("tickets.event_id IN ?", eventIDs)
+	}
+
+	err := query.Scan(&stats).Error
+	return stats, err
+}
+
+// FindSalesByEventID mengambil waktu beli dan batal semua tiket sebuah event. Tiket yang
+// dibatalkan sebelum kolom cancelled_at ada memakai updated_at sebagai waktu batal.
+func (r *ticketRepository) FindSalesByEventID(eventID string) ([]TicketSale, error) {
+	var sales []TicketSale
+	err := r.scoped().Model(&entity.Ticket{}).
+		Select("purchase_date, price, CASE WHEN status = ? THEN COALESCE(cancelled_at, updated_at) END AS cancelled_at", entity.CancelledTicket).
+		Where("event_id = ? AND status IN ?", eventID, []entity.TicketStatus{entity.PurchasedTicket, entity.CancelledTicket}).
+		Order("purchase_date ASC").
+		Scan(&sales).Error
+	return sales, err
+}
+
+// EachAttendeeByEventID membaca daftar peserta baris demi baris lewat cursor database
+// sehingga event dengan banyak tiket tidak perlu dimuat sekaligus ke memori
+func (r *ticketRepository) EachAttendeeByEventID(eventID string, fn func(attendee Attendee) error) error {
+	rows, err := r.db.Model(&entity.Ticket{}).
+		Select(`tickets.id AS ticket_id, tickets.booking_code, tickets.status, tickets.price,
+			tickets.purchase_date, tickets.cancelled_at, tickets.checked_in_at, tickets.check_in_gate,
+			users.name AS user_name, users.email AS user_email`).
+		Joins("LEFT JOIN users ON users.id = tickets.user_id").
+		Where("tickets.organization_id = ? AND tickets.event_id = ?", r.organizationID, eventID).
+		Order("tickets.purchase_date ASC").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attendee Attendee
+		if err := r.db.ScanRows(rows, &attendee); err != nil {
+			return err
+		}
+		if err := fn(attendee); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// CheckIn menandai tiket sudah hadir secara atomik sehingga tiket tidak bisa dipindai dua kali
+func (r *ticketRepository) CheckIn(id, gate string, at time.Time) (bool, error) {
+	result := r.scoped().Model(&entity.Ticket{}).
+		Where("id = ? AND status = ? AND checked_in_at IS NULL", id, entity.PurchasedTicket).
+		Updates(map[string]interface{}{"checked_in_at": at, "check_in_gate": gate})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *ticketRepository) FindCheckInTimesByEventID(eventID string) ([]time.Time, error) {
+	var times []time.Time
+	err := r.scoped().Model(&entity.Ticket{}).
+		Where("event_id = ? AND status = ? AND checked_in_at IS NOT NULL", eventID, entity.PurchasedTicket).
+		Order("checked_in_at ASC").
+		Pluck("checked_in_at", &times).Error
+	return times, err
+}
+
+func (r *ticketRepository) CountCheckInsByGate(eventID string) ([]GateCount, error) {
+	var gates []GateCount
+	err := r.scoped().Model(&entity.Ticket{}).
+		Select("check_in_gate AS gate, COUNT(*) AS count").
+		Where("event_id = ? AND status = ? AND checked_in_at IS NOT NULL", eventID, entity.PurchasedTicket).
+		Group("check_in_gate").
+		Order("count DESC").
+		Scan(&gates).Error
+	return gates, err
+}
+
+// FindNoShowsByEventID mengambil tiket terjual yang belum check-in
+func (r *ticketRepository) FindNoShowsByEventID(eventID string) ([]Attendee, error) {
+	var attendees []Attendee
+	err := r.db.Model(&entity.Ticket{}).
+		Select(`tickets.id AS ticket_id, tickets.booking_code, tickets.status, tickets.price,
+			tickets.purchase_date, tickets.cancelled_at, tickets.checked_in_at, tickets.check_in_gate,
+			users.name AS user_name, users.email AS user_email`).
+		Joins("LEFT JOIN users ON users.id = tickets.user_id").
+		Where("tickets.organization_id = ? AND tickets.event_id = ? AND tickets.status = ? AND tickets.checked_in_at IS NULL",
+			r.organizationID, eventID, entity.PurchasedTicket).
+		Order("users.name ASC").
+		Scan(&attendees).Error
+	return attendees, err
+}
+
+// FindHolderIDsByEventID mengembalikan user yang memegang tiket aktif sebuah event
+func (r *ticketRepository) FindHolderIDsByEventID(eventID string) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.scoped().Model(&entity.Ticket{}).
+		Where("event_id = ? AND status = ?", eventID, entity.PurchasedTicket).
+		Distinct().
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
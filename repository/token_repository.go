@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TokenRepository interface {
+	CreateRefreshToken(token *entity.RefreshToken) error
+	FindRefreshTokenByHash(hash string) (*entity.RefreshToken, error)
+	FindRefreshTokenByAccessTokenID(tokenID string) (*entity.RefreshToken, error)
+	FindRefreshTokensBySessionID(sessionID string) ([]entity.RefreshToken, error)
+	FindRefreshTokensByUserID(userID string) ([]entity.RefreshToken, error)
+	RevokeRefreshToken(id string) (bool, error)
+	RevokeAccessToken(tokenID string, expiresAt time.Time) error
+	IsAccessTokenRevoked(tokenID string) (bool, error)
+	WithTenant(organizationID string) TokenRepository
+}
+
+type tokenRepository struct {
+	db             *gorm.DB
+	organizationID uuid.UUID
+}
+
+func NewTokenRepository(db *gorm.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+func (r *tokenRepository) WithTenant(organizationID string) TokenRepository {
+	return &tokenRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *tokenRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
+}
+
+func (r *tokenRepository) CreateRefreshToken(token *entity.RefreshToken) error {
+	token.OrganizationID = r.organizationID
+	return r.db.Create(token).Error
+}
+
+func (r *tokenRepository) FindRefreshTokenByHash(hash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	err := r.scoped().Where("token_hash = ?", hash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("refresh token not found")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *tokenRepository) FindRefreshTokenByAccessTokenID(tokenID string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	err := r.scoped().Where("access_token_id = ?", tokenID).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("session not found")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *tokenRepository) FindRefreshTokensBySessionID(sessionID string) ([]entity.RefreshToken, error) {
+	var tokens []entity.RefreshToken
+	err := r.scoped().Where("session_id = ?", sessionID).Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *tokenRepository) FindRefreshTokensByUserID(userID string) ([]entity.RefreshToken, error) {
+	var tokens []entity.RefreshToken
+	err := r.scoped().
+		Where("user_id = ? AND (revoked_at IS NULL OR access_expires_at > ?)", userID, time.Now()).
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// RevokeRefreshToken mencabut refresh token secara atomik dan mengembalikan false
+// jika token tersebut sudah dicabut oleh request lain
+func (r *tokenRepository) RevokeRefreshToken(id string) (bool, error) {
+	result := r.scoped().Model(&entity.RefreshToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *tokenRepository) RevokeAccessToken(tokenID string, expiresAt time.Time) error {
+	if err := r.db.Where("expires_at < ?", time.Now()).Delete(&entity.RevokedToken{}).Error; err != nil {
+		return err
+	}
+
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&entity.RevokedToken{TokenID: tokenID, ExpiresAt: expiresAt}).Error
+}
+
+func (r *tokenRepository) IsAccessTokenRevoked(tokenID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&entity.RevokedToken{}).Where("token_id = ?", tokenID).Count(&count).Error
+	return count > 0, err
+}
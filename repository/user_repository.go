@@ -3,7 +3,10 @@ package repository
 import (
 	"errors"
 	"event-ticketing/entity"
+	"event-ticketing/utils"
+	"time"
 
+	"github.com/gofrs/uuid/v5"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -12,16 +15,34 @@ type UserRepository interface {
 	Create(user *entity.User) error
 	FindByID(id string) (*entity.User, error)
 	FindByEmail(email string) (*entity.User, error)
+	FindAll(params utils.PaginationParams, keyword, role string) ([]entity.User, int64, error)
+	CountActiveByRole(role entity.Role) (int64, error)
 	Update(user *entity.User) error
+	UpdatePassword(id string, password string) error
+	MarkEmailVerified(id string) error
+	ConfirmEmailChange(id string, email string) error
+	UpdateTwoFactor(user *entity.User) error
+	UpdateRole(id string, role entity.Role) error
+	UpdateSuspendedAt(id string, suspendedAt *time.Time) error
 	Delete(id string) error
+	WithTenant(organizationID string) UserRepository
 }
 
 type userRepository struct {
-	db *gorm.DB
+	db             *gorm.DB
+	organizationID uuid.UUID
 }
 
 func NewUserRepository(db *gorm.DB) UserRepository {
-	return &userRepository{db}
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) WithTenant(organizationID string) UserRepository {
+	return &userRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *userRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
 }
 
 func (r *userRepository) Create(user *entity.User) error {
@@ -30,13 +51,14 @@ func (r *userRepository) Create(user *entity.User) error {
 		return err
 	}
 	user.Password = string(hashedPassword)
+	user.OrganizationID = r.organizationID
 
 	return r.db.Create(user).Error
 }
 
 func (r *userRepository) FindByID(id string) (*entity.User, error) {
 	var user entity.User
-	err := r.db.Where("id = ?", id).First(&user).Error
+	err := r.scoped().Where("id = ?", id).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("user not found")
@@ -48,7 +70,7 @@ func (r *userRepository) FindByID(id string) (*entity.User, error) {
 
 func (r *userRepository) FindByEmail(email string) (*entity.User, error) {
 	var user entity.User
-	err := r.db.Where("email = ?", email).First(&user).Error
+	err := r.scoped().Where("email = ?", email).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.New("user not found")
@@ -58,10 +80,94 @@ func (r *userRepository) FindByEmail(email string) (*entity.User, error) {
 	return &user, nil
 }
 
+func (r *userRepository) FindAll(params utils.PaginationParams, keyword, role string) ([]entity.User, int64, error) {
+	var users []entity.User
+	var count int64
+
+	query := r.scoped().Model(&entity.User{})
+	if keyword != "" {
+		query = query.Where("name LIKE ? OR email LIKE ?", "%"+keyword+"%", "%"+keyword+"%")
+	}
+
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC").Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, count, nil
+}
+
+// CountActiveByRole menghitung user dengan role tertentu yang tidak sedang di-suspend
+func (r *userRepository) CountActiveByRole(role entity.Role) (int64, error) {
+	var count int64
+	err := r.scoped().Model(&entity.User{}).Where("role = ? AND suspended_at IS NULL", role).Count(&count).Error
+	return count, err
+}
+
+// Update hanya menyimpan kolom profil yang bisa diubah user sendiri. Kolom lain seperti
+// password, role, status suspend, dan 2FA punya method tersendiri agar perubahan paralel
+// dari alur lain tidak tertimpa oleh data lama.
 func (r *userRepository) Update(user *entity.User) error {
-	return r.db.Save(user).Error
+	if user.OrganizationID != r.organizationID {
+		return errors.New("user not found")
+	}
+	return r.scoped().Model(user).
+		Select("name", "pending_email", "language", "event_reminders_opt_out").
+		Updates(user).Error
 }
 
+func (r *userRepository) UpdatePassword(id string, password string) error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return r.scoped().Model(&entity.User{}).Where("id = ?", id).Update("password", string(hashedPassword)).Error
+}
+
+func (r *userRepository) MarkEmailVerified(id string) error {
+	return r.scoped().Model(&entity.User{}).Where("id = ?", id).Update("email_verified_at", time.Now()).Error
+}
+
+func (r *userRepository) ConfirmEmailChange(id string, email string) error {
+	return r.scoped().Model(&entity.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"email":             email,
+		"pending_email":     "",
+		"email_verified_at": time.Now(),
+	}).Error
+}
+
+func (r *userRepository) UpdateTwoFactor(user *entity.User) error {
+	return r.scoped().Model(user).
+		Select("totp_secret", "totp_last_step", "two_factor_enabled_at").
+		Updates(user).Error
+}
+
+func (r *userRepository) UpdateRole(id string, role entity.Role) error {
+	return r.scoped().Model(&entity.User{}).Where("id = ?", id).Update("role", role).Error
+}
+
+func (r *userRepository) UpdateSuspendedAt(id string, suspendedAt *time.Time) error {
+	return r.scoped().Model(&entity.User{}).Where("id = ?", id).Update("suspended_at", suspendedAt).Error
+}
+
+// Delete menghapus data pribadi user sebelum melakukan soft delete, sehingga baris
+// yang tersisa untuk tiket dan laporan keuangan tidak lagi berisi PII
 func (r *userRepository) Delete(id string) error {
-	return r.db.Delete(&entity.User{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Scopes(tenantScope(r.organizationID)).Model(&entity.User{}).Where("id = ?", id).
+			Updates(entity.AnonymizedUserColumns(id)).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Scopes(tenantScope(r.organizationID)).Where("id = ?", id).Delete(&entity.User{}).Error
+	})
 }
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"event-ticketing/entity"
+	"testing"
+	"time"
+)
+
+func TestUserRepositoryUpdateKeepsOtherColumns(t *testing.T) {
+	db := openTestDB(t)
+
+	organization := seedOrganization(t, db)
+	repo := NewUserRepository(db).WithTenant(organization.ID.String())
+	user := seedUser(t, db, organization.ID)
+
+	// Salinan lama dibaca sebelum admin men-suspend dan menaikkan role user
+	stale, err := repo.FindByID(user.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	suspendedAt := time.Now().Truncate(time.Second)
+	if err := repo.UpdateSuspendedAt(user.ID.String(), &suspendedAt); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.UpdateRole(user.ID.String(), entity.AdminRole); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.UpdatePassword(user.ID.String(), "new-password"); err != nil {
+		t.Fatal(err)
+	}
+
+	stale.Name = "Renamed User"
+	stale.Language = entity.EnglishLanguage
+	stale.EventRemindersOptOut = true
+	if err := repo.Update(stale); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := repo.FindByID(user.ID.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Renamed User" || updated.Language != entity.EnglishLanguage || !updated.EventRemindersOptOut {
+		t.Fatalf("profile fields were not saved: %+v", updated)
+	}
+	if updated.SuspendedAt == nil || updated.Role != entity.AdminRole {
+		t.Fatalf("concurrent changes were overwritten: suspended_at=%v role=%s", updated.SuspendedAt, updated.Role)
+	}
+	if updated.Password == stale.Password {
+		t.Fatal("password was overwritten with the stale hash")
+	}
+}
+
+func TestCountActiveByRoleSkipsSuspendedUsers(t *testing.T) {
+	db := openTestDB(t)
+
+	organization := seedOrganization(t, db)
+	repo := NewUserRepository(db).WithTenant(organization.ID.String())
+
+	for i := 0; i < 2; i++ {
+		admin := seedUser(t, db, organization.ID)
+		if err := repo.UpdateRole(admin.ID.String(), entity.AdminRole); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			suspendedAt := time.Now()
+			if err := repo.UpdateSuspendedAt(admin.ID.String(), &suspendedAt); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	count, err := repo.CountActiveByRole(entity.AdminRole)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("CountActiveByRole(admin) = %d, want 1", count)
+	}
+}
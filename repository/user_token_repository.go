@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+)
+
+type UserTokenRepository interface {
+	Create(token *entity.UserToken) error
+	FindByHash(purpose entity.UserTokenPurpose, hash string) (*entity.UserToken, error)
+	MarkUsed(id string) (bool, error)
+	InvalidateByUserID(userID string, purpose entity.UserTokenPurpose) error
+	WithTenant(organizationID string) UserTokenRepository
+}
+
+type userTokenRepository struct {
+	db             *gorm.DB
+	organizationID uuid.UUID
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+func (r *userTokenRepository) WithTenant(organizationID string) UserTokenRepository {
+	return &userTokenRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *userTokenRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
+}
+
+func (r *userTokenRepository) Create(token *entity.UserToken) error {
+	token.OrganizationID = r.organizationID
+	return r.db.Create(token).Error
+}
+
+func (r *userTokenRepository) FindByHash(purpose entity.UserTokenPurpose, hash string) (*entity.UserToken, error) {
+	var token entity.UserToken
+	err := r.scoped().Where("purpose = ? AND token_hash = ?", purpose, hash).First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("token not found")
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed menandai token sudah dipakai secara atomik sehingga token hanya bisa dipakai sekali
+func (r *userTokenRepository) MarkUsed(id string) (bool, error) {
+	result := r.scoped().Model(&entity.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *userTokenRepository) InvalidateByUserID(userID string, purpose entity.UserTokenPurpose) error {
+	return r.scoped().Model(&entity.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
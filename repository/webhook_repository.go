@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"event-ticketing/utils"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookDeliveryFilter berisi filter opsional untuk mencari pengiriman webhook
+type WebhookDeliveryFilter struct {
+	SubscriptionID string
+	Status         string
+	EventType      string
+}
+
+type WebhookRepository interface {
+	CreateSubscription(subscription *entity.WebhookSubscription) error
+	FindSubscriptionByID(id string) (*entity.WebhookSubscription, error)
+	FindAllSubscriptions(params utils.PaginationParams) ([]entity.WebhookSubscription, int64, error)
+	FindSubscriptionsByEventType(eventType entity.WebhookEventType) ([]entity.WebhookSubscription, error)
+	DeleteSubscription(id string) error
+	CreateDelivery(delivery *entity.WebhookDelivery) error
+	FindDeliveryByID(id string) (*entity.WebhookDelivery, error)
+	FindAllDeliveries(params utils.PaginationParams, filter WebhookDeliveryFilter) ([]entity.WebhookDelivery, int64, error)
+	ResetDelivery(id string, nextAttemptAt time.Time) error
+	FindDueDeliveries(now time.Time, limit int) ([]entity.WebhookDelivery, error)
+	ClaimDelivery(id uuid.UUID, previousAttemptAt, leaseUntil time.Time) (bool, error)
+	RecordDeliveryAttempt(delivery *entity.WebhookDelivery) error
+	WithTenant(organizationID string) WebhookRepository
+}
+
+type webhookRepository struct {
+	db             *gorm.DB
+	organizationID uuid.UUID
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) WithTenant(organizationID string) WebhookRepository {
+	return &webhookRepository{db: r.db, organizationID: uuid.FromStringOrNil(organizationID)}
+}
+
+func (r *webhookRepository) scoped() *gorm.DB {
+	return r.db.Scopes(tenantScope(r.organizationID))
+}
+
+func (r *webhookRepository) CreateSubscription(subscription *entity.WebhookSubscription) error {
+	subscription.OrganizationID = r.organizationID
+	return r.db.Create(subscription).Error
+}
+
+func (r *webhookRepository) FindSubscriptionByID(id string) (*entity.WebhookSubscription, error) {
+	var subscription entity.WebhookSubscription
+	err := r.scoped().Where("id = ?", id).First(&subscription).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook subscription not found")
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *webhookRepository) FindAllSubscriptions(params utils.PaginationParams) ([]entity.WebhookSubscription, int64, error) {
+	var subscriptions []entity.WebhookSubscription
+	var count int64
+
+	if err := r.scoped().Model(&entity.WebhookSubscription{}).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.scoped().Order("created_at DESC").Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&subscriptions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return subscriptions, count, nil
+}
+
+func (r *webhookRepository) FindSubscriptionsByEventType(eventType entity.WebhookEventType) ([]entity.WebhookSubscription, error) {
+	var subscriptions []entity.WebhookSubscription
+	err := r.scoped().Where("FIND_IN_SET(?, event_types) > 0", eventType).Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) DeleteSubscription(id string) error {
+	return r.scoped().Where("id = ?", id).Delete(&entity.WebhookSubscription{}).Error
+}
+
+// CreateDelivery mengabaikan pengiriman yang sudah ada untuk subscription dan pesan yang
+// sama, sehingga domain event yang dikirim ulang tidak membuat pengiriman ganda
+func (r *webhookRepository) CreateDelivery(delivery *entity.WebhookDelivery) error {
+	delivery.OrganizationID = r.organizationID
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(delivery).Error
+}
+
+func (r *webhookRepository) FindDeliveryByID(id string) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	err := r.scoped().Where("id = ?", id).First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("webhook delivery not found")
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) FindAllDeliveries(params utils.PaginationParams, filter WebhookDeliveryFilter) ([]entity.WebhookDelivery, int64, error) {
+	var deliveries []entity.WebhookDelivery
+	var count int64
+
+	query := r.scoped().Model(&entity.WebhookDelivery{})
+
+	if filter.SubscriptionID != "" {
+		query = query.Where("subscription_id = ?", filter.SubscriptionID)
+	}
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("created_at DESC").Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, count, nil
+}
+
+// ResetDelivery menjadwalkan ulang pengiriman dari awal dengan jumlah percobaan kembali nol
+func (r *webhookRepository) ResetDelivery(id string, nextAttemptAt time.Time) error {
+	return r.scoped().Model(&entity.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          entity.PendingWebhookDelivery,
+			"attempts":        0,
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      "",
+		}).Error
+}
+
+// FindDueDeliveries mencari pengiriman yang sudah waktunya dicoba, lintas organisasi
+func (r *webhookRepository) FindDueDeliveries(now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	err := r.db.Preload("Subscription").
+		Where("status = ? AND next_attempt_at <= ?", entity.PendingWebhookDelivery, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// ClaimDelivery menunda percobaan berikutnya sampai leaseUntil hanya jika belum diambil
+// proses lain. Jika proses mati di tengah pengiriman, pengiriman dicoba lagi setelah lease habis.
+func (r *webhookRepository) ClaimDelivery(id uuid.UUID, previousAttemptAt, leaseUntil time.Time) (bool, error) {
+	result := r.db.Model(&entity.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", id, entity.PendingWebhookDelivery, previousAttemptAt).
+		Update("next_attempt_at", leaseUntil)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *webhookRepository) RecordDeliveryAttempt(delivery *entity.WebhookDelivery) error {
+	return r.db.Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "last_attempt_at", "response_status", "response_body", "last_error", "delivered_at").
+		Updates(delivery).Error
+}
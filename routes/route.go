@@ -2,11 +2,18 @@
 package routes
 
 import (
+	"event-ticketing/broker"
 	"event-ticketing/config"
 	"event-ticketing/controller"
+	"event-ticketing/domain"
+	"event-ticketing/entity"
+	"event-ticketing/mailer"
 	"event-ticketing/middleware"
+	"event-ticketing/notification"
+	"event-ticketing/oidc"
 	"event-ticketing/repository"
 	"event-ticketing/service"
+	"event-ticketing/utils"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -20,70 +27,197 @@ func SetupRoutes(db *gorm.DB, config config.Config) *gin.Engine {
 	userRepo := repository.NewUserRepository(db)
 	eventRepo := repository.NewEventRepository(db)
 	ticketRepo := repository.NewTicketRepository(db)
+	organizationRepo := repository.NewOrganizationRepository(db)
+	tokenRepo := repository.NewTokenRepository(db)
+	signingKeyRepo := repository.NewSigningKeyRepository(db)
+	userTokenRepo := repository.NewUserTokenRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	loginThrottleRepo := repository.NewLoginThrottleRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	oidcRepo := repository.NewOIDCRepository(db)
+	queueRepo := repository.NewQueueRepository(db)
+	reportSubscriptionRepo := repository.NewReportSubscriptionRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	ticketReminderRepo := repository.NewTicketReminderRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+
+	// Initialize JWT signing keys
+	keyStore := middleware.NewKeyStore(signingKeyRepo, config)
+	if err := keyStore.Start(); err != nil {
+		utils.Log.Fatalf("Failed to load JWT signing keys: %v", err)
+	}
+
+	// Initialize mailer
+	mail := mailer.New(config)
+
+	// In-process pub/sub for live updates
+	bus := broker.NewMemoryBroker()
+
+	// Transactional emails to ticket holders
+	notifier := notification.NewNotifier(notificationRepo, userRepo, ticketRepo, mail, config)
+	notifier.Start()
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, config)
-	eventService := service.NewEventService(eventRepo, ticketRepo)
-	ticketService := service.NewTicketService(db, ticketRepo, eventRepo)
+	auditService := service.NewAuditService(auditLogRepo)
+	loginGuard := service.NewLoginGuard(loginThrottleRepo, auditLogRepo, config)
+	authService := service.NewAuthService(userRepo, tokenRepo, userTokenRepo, recoveryCodeRepo, loginGuard, auditService, keyStore, mail, config)
+	webhookService := service.NewWebhookService(webhookRepo, auditService, config)
+	service.NewWebhookDispatcher(webhookRepo, config).Start()
+
+	// Side effects of committed changes, relayed from the outbox
+	domainBus := domain.NewBus()
 	reportService := service.NewReportService(eventRepo, userRepo, ticketRepo)
+	service.RegisterDomainEventHandlers(domainBus, bus, notifier, webhookService, reportService)
+	service.NewOutboxRelay(outboxRepo, domainBus, config).Start()
+
+	eventService := service.NewEventService(db, eventRepo, ticketRepo, outboxRepo, auditService)
+	ticketService := service.NewTicketService(db, ticketRepo, eventRepo, queueRepo, outboxRepo, auditService)
+	accountService := service.NewAccountService(userRepo, ticketRepo, tokenRepo, recoveryCodeRepo, auditLogRepo, apiKeyRepo, oidcRepo, notificationRepo, authService, loginGuard)
+	userService := service.NewUserService(userRepo, authService, accountService, loginGuard, auditService)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, userRepo, auditService)
+	oidcService := service.NewOIDCService(oidcRepo, userRepo, authService, oidc.NewProviders(config.OIDCProviders))
+	queueService := service.NewQueueService(queueRepo, eventRepo)
+	service.NewQueueAdmitter(queueRepo, config).Start()
+	service.NewInventoryReconciler(eventRepo, config).Start()
+	reportSubscriptionService := service.NewReportSubscriptionService(reportSubscriptionRepo, eventRepo, auditService)
+	service.NewReportScheduler(reportSubscriptionRepo, reportService, mail, config).Start()
+	service.NewReminderScheduler(db, ticketReminderRepo, notifier, config).Start()
 
 	// Initialize controllers
 	authController := controller.NewAuthController(authService)
+	accountController := controller.NewAccountController(accountService)
 	eventController := controller.NewEventController(eventService)
 	ticketController := controller.NewTicketController(ticketService)
-	reportController := controller.NewReportController(reportService)
+	reportController := controller.NewReportController(reportService, bus)
+	userController := controller.NewUserController(userService)
+	apiKeyController := controller.NewAPIKeyController(apiKeyService)
+	oidcController := controller.NewOIDCController(oidcService)
+	auditLogController := controller.NewAuditLogController(auditService)
+	queueController := controller.NewQueueController(queueService)
+	reportSubscriptionController := controller.NewReportSubscriptionController(reportSubscriptionService)
+	webhookController := controller.NewWebhookController(webhookService)
 
 	// Create router
 	router := gin.Default()
+	router.Use(middleware.RequestIDMiddleware())
 
 	// Auth routes
 	authRoutes := router.Group("/api/auth")
 	{
-		authRoutes.POST("/register", authController.Register)
-		authRoutes.POST("/login", authController.Login)
-		authRoutes.GET("/profile", middleware.AuthMiddleware(userRepo, config), authController.GetProfile)
+		authRoutes.POST("/register", middleware.TenantMiddleware(organizationRepo), authController.Register)
+		authRoutes.POST("/login", middleware.TenantMiddleware(organizationRepo), authController.Login)
+		authRoutes.POST("/login/2fa", middleware.TenantMiddleware(organizationRepo), authController.VerifyTwoFactorLogin)
+		authRoutes.GET("/oidc/providers", oidcController.GetProviders)
+		authRoutes.GET("/oidc/:provider/login", middleware.TenantMiddleware(organizationRepo), oidcController.StartLogin)
+		authRoutes.POST("/oidc/:provider/callback", oidcController.Callback)
+		authRoutes.POST("/refresh", middleware.TenantMiddleware(organizationRepo), authController.RefreshToken)
+		authRoutes.POST("/logout", middleware.AuthMiddleware(userRepo, tokenRepo, keyStore), authController.Logout)
+		authRoutes.POST("/logout-all", middleware.AuthMiddleware(userRepo, tokenRepo, keyStore), authController.LogoutAll)
+		authRoutes.POST("/forgot-password", middleware.TenantMiddleware(organizationRepo), authController.ForgotPassword)
+		authRoutes.POST("/reset-password", middleware.TenantMiddleware(organizationRepo), authController.ResetPassword)
+		authRoutes.POST("/verify-email", middleware.TenantMiddleware(organizationRepo), authController.VerifyEmail)
+		authRoutes.POST("/verify-email/resend", middleware.AuthMiddleware(userRepo, tokenRepo, keyStore), authController.ResendVerification)
+		authRoutes.POST("/2fa/setup", middleware.AuthMiddleware(userRepo, tokenRepo, keyStore), authController.SetupTwoFactor)
+		authRoutes.POST("/2fa/enable", middleware.AuthMiddleware(userRepo, tokenRepo, keyStore), authController.EnableTwoFactor)
+		authRoutes.POST("/2fa/disable", middleware.AuthMiddleware(userRepo, tokenRepo, keyStore), authController.DisableTwoFactor)
+		authRoutes.GET("/profile", middleware.AuthMiddleware(userRepo, tokenRepo, keyStore), authController.GetProfile)
+		authRoutes.PUT("/profile", middleware.AuthMiddleware(userRepo, tokenRepo, keyStore), authController.UpdateProfile)
+		authRoutes.PUT("/password", middleware.AuthMiddleware(userRepo, tokenRepo, keyStore), authController.ChangePassword)
+		authRoutes.GET("/me/export", middleware.AuthMiddleware(userRepo, tokenRepo, keyStore), accountController.ExportData)
+		authRoutes.DELETE("/me", middleware.AuthMiddleware(userRepo, tokenRepo, keyStore), accountController.DeleteAccount)
+		authRoutes.GET("/me/notifications", middleware.AuthMiddleware(userRepo, tokenRepo, keyStore), accountController.GetNotifications)
 	}
 
 	// Event routes
 	eventRoutes := router.Group("/api/events")
 	{
-		eventRoutes.GET("", eventController.GetAllEvents)
-		eventRoutes.GET("/:id", eventController.GetEventByID)
+		eventRoutes.GET("", middleware.TenantMiddleware(organizationRepo), eventController.GetAllEvents)
+		eventRoutes.GET("/:id", middleware.TenantMiddleware(organizationRepo), eventController.GetEventByID)
 
 		// Protected routes
-		eventRoutes.POST("", middleware.AuthMiddleware(userRepo, config), middleware.AdminMiddleware(), eventController.CreateEvent)
-		eventRoutes.PUT("/:id", middleware.AuthMiddleware(userRepo, config), middleware.AdminMiddleware(), eventController.UpdateEvent)
-		eventRoutes.DELETE("/:id", middleware.AuthMiddleware(userRepo, config), middleware.AdminMiddleware(), eventController.DeleteEvent)
+		eventRoutes.POST("", middleware.APIKeyMiddleware(userRepo, tokenRepo, apiKeyRepo, keyStore, entity.EventsWriteScope), middleware.AdminMiddleware(config), eventController.CreateEvent)
+		eventRoutes.PUT("/:id", middleware.APIKeyMiddleware(userRepo, tokenRepo, apiKeyRepo, keyStore, entity.EventsWriteScope), middleware.AdminMiddleware(config), eventController.UpdateEvent)
+		eventRoutes.DELETE("/:id", middleware.APIKeyMiddleware(userRepo, tokenRepo, apiKeyRepo, keyStore, entity.EventsWriteScope), middleware.AdminMiddleware(config), eventController.DeleteEvent)
 
 		// Admin route for event tickets
-		eventRoutes.GET("/:id/tickets", middleware.AuthMiddleware(userRepo, config), middleware.AdminMiddleware(), ticketController.GetEventTickets)
+		eventRoutes.GET("/:id/tickets", middleware.APIKeyMiddleware(userRepo, tokenRepo, apiKeyRepo, keyStore, entity.TicketsReadScope), middleware.AdminMiddleware(config), ticketController.GetEventTickets)
+
+		// Waiting room for queued on-sales
+		eventRoutes.POST("/:id/queue", middleware.APIKeyMiddleware(userRepo, tokenRepo, apiKeyRepo, keyStore, entity.TicketsWriteScope), middleware.VerifiedEmailMiddleware(config), queueController.JoinQueue)
+		eventRoutes.GET("/:id/queue", middleware.APIKeyMiddleware(userRepo, tokenRepo, apiKeyRepo, keyStore, entity.TicketsReadScope), queueController.GetQueueStatus)
 	}
 
 	// Ticket routes
 	ticketRoutes := router.Group("/api/tickets")
 	{
-		ticketRoutes.Use(middleware.AuthMiddleware(userRepo, config))
-
-		ticketRoutes.POST("", ticketController.BuyTicket)
-		ticketRoutes.GET("/my-tickets", ticketController.GetUserTickets)
-		ticketRoutes.GET("/:id", middleware.AdminMiddleware(), ticketController.GetTicketByID)
-		ticketRoutes.PUT("/:id/cancel", middleware.AdminMiddleware(), ticketController.CancelTicket)
+		ticketRead := middleware.APIKeyMiddleware(userRepo, tokenRepo, apiKeyRepo, keyStore, entity.TicketsReadScope)
+		ticketWrite := middleware.APIKeyMiddleware(userRepo, tokenRepo, apiKeyRepo, keyStore, entity.TicketsWriteScope)
+
+		ticketRoutes.POST("", ticketWrite, middleware.VerifiedEmailMiddleware(config), ticketController.BuyTicket)
+		ticketRoutes.GET("/my-tickets", ticketRead, ticketController.GetUserTickets)
+		ticketRoutes.POST("/check-in", ticketWrite, middleware.AdminMiddleware(config), ticketController.CheckIn)
+		ticketRoutes.GET("/:id", ticketRead, middleware.AdminMiddleware(config), ticketController.GetTicketByID)
+		ticketRoutes.PUT("/:id/cancel", ticketWrite, middleware.AdminMiddleware(config), ticketController.CancelTicket)
 	}
 
 	// Report routes (admin only)
 	reportRoutes := router.Group("/api/reports")
 	{
-		reportRoutes.Use(middleware.AuthMiddleware(userRepo, config))
-		reportRoutes.Use(middleware.AdminMiddleware())
+		reportRoutes.Use(middleware.APIKeyMiddleware(userRepo, tokenRepo, apiKeyRepo, keyStore, entity.ReportsReadScope))
+		reportRoutes.Use(middleware.AdminMiddleware(config))
 
 		reportRoutes.GET("/summary", reportController.GetSummaryReport)
 		reportRoutes.GET("/events/:id", reportController.GetEventReport)
+		reportRoutes.GET("/events/:id/sales", reportController.GetEventSales)
+		reportRoutes.GET("/events/:id/stream", reportController.StreamEventReport)
+	}
+
+	// Admin user management routes
+	adminRoutes := router.Group("/api/admin")
+	{
+		adminRoutes.Use(middleware.AuthMiddleware(userRepo, tokenRepo, keyStore))
+		adminRoutes.Use(middleware.AdminMiddleware(config))
+
+		adminRoutes.GET("/users", userController.GetAllUsers)
+		adminRoutes.GET("/users/:id", userController.GetUserByID)
+		adminRoutes.PUT("/users/:id/promote", userController.PromoteUser)
+		adminRoutes.PUT("/users/:id/demote", userController.DemoteUser)
+		adminRoutes.PUT("/users/:id/suspend", userController.SuspendUser)
+		adminRoutes.PUT("/users/:id/unsuspend", userController.UnsuspendUser)
+		adminRoutes.PUT("/users/:id/unlock", userController.UnlockUser)
+		adminRoutes.DELETE("/users/:id", userController.DeleteUser)
+		adminRoutes.GET("/users/:id/notifications", userController.GetUserNotifications)
+
+		adminRoutes.GET("/api-keys", apiKeyController.GetAllAPIKeys)
+		adminRoutes.POST("/api-keys", apiKeyController.CreateAPIKey)
+		adminRoutes.DELETE("/api-keys/:id", apiKeyController.RevokeAPIKey)
+
+		adminRoutes.GET("/audit-logs", auditLogController.GetAuditLogs)
+
+		adminRoutes.GET("/report-subscriptions", reportSubscriptionController.GetAllSubscriptions)
+		adminRoutes.POST("/report-subscriptions", reportSubscriptionController.CreateSubscription)
+		adminRoutes.DELETE("/report-subscriptions/:id", reportSubscriptionController.DeleteSubscription)
+
+		adminRoutes.GET("/webhooks", webhookController.GetAllWebhooks)
+		adminRoutes.POST("/webhooks", webhookController.CreateWebhook)
+		adminRoutes.DELETE("/webhooks/:id", webhookController.DeleteWebhook)
+		adminRoutes.GET("/webhook-deliveries", webhookController.GetDeliveries)
+		adminRoutes.GET("/webhook-deliveries/:id", webhookController.GetDelivery)
+		adminRoutes.POST("/webhook-deliveries/:id/replay", webhookController.ReplayDelivery)
 	}
 
 	if config.Environment != "production" {
 		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	}
 
+	// Public keys for verifying access tokens in other services
+	router.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(200, keyStore.JWKS())
+	})
+
 	// Add health check route
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
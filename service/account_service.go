@@ -0,0 +1,229 @@
+package service
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"io"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AccountExport berisi seluruh data pribadi user untuk permintaan akses data (GDPR).
+// Setiap tiket adalah satu pesanan, sehingga riwayat pesanan diambil dari tiket.
+type AccountExport struct {
+	ExportedAt time.Time             `json:"exported_at"`
+	Profile    AccountProfile        `json:"profile"`
+	Tickets    []entity.Ticket       `json:"tickets"`
+	Sessions   []AccountSession      `json:"sessions"`
+	Identities []entity.UserIdentity `json:"identities"`
+}
+
+type AccountProfile struct {
+	*entity.User
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AccountSession struct {
+	SessionID uuid.UUID  `json:"session_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+type AccountService interface {
+	ExportData(userID string) (*AccountExport, error)
+	DeleteAccount(userID, password string) error
+	EraseUser(user *entity.User) error
+	GetNotifications(userID string, params utils.PaginationParams) ([]entity.Notification, int64, error)
+	WithTenant(organizationID string) AccountService
+}
+
+type accountService struct {
+	userRepo         repository.UserRepository
+	ticketRepo       repository.TicketRepository
+	tokenRepo        repository.TokenRepository
+	recoveryRepo     repository.RecoveryCodeRepository
+	auditRepo        repository.AuditLogRepository
+	apiKeyRepo       repository.APIKeyRepository
+	oidcRepo         repository.OIDCRepository
+	notificationRepo repository.NotificationRepository
+	authService      AuthService
+	loginGuard       LoginGuard
+}
+
+func NewAccountService(
+	userRepo repository.UserRepository,
+	ticketRepo repository.TicketRepository,
+	tokenRepo repository.TokenRepository,
+	recoveryRepo repository.RecoveryCodeRepository,
+	auditRepo repository.AuditLogRepository,
+	apiKeyRepo repository.APIKeyRepository,
+	oidcRepo repository.OIDCRepository,
+	notificationRepo repository.NotificationRepository,
+	authService AuthService,
+	loginGuard LoginGuard,
+) AccountService {
+	return &accountService{
+		userRepo:         userRepo,
+		ticketRepo:       ticketRepo,
+		tokenRepo:        tokenRepo,
+		recoveryRepo:     recoveryRepo,
+		auditRepo:        auditRepo,
+		apiKeyRepo:       apiKeyRepo,
+		oidcRepo:         oidcRepo,
+		notificationRepo: notificationRepo,
+		authService:      authService,
+		loginGuard:       loginGuard,
+	}
+}
+
+func (s *accountService) WithTenant(organizationID string) AccountService {
+	return &accountService{
+		userRepo:         s.userRepo.WithTenant(organizationID),
+		ticketRepo:       s.ticketRepo.WithTenant(organizationID),
+		tokenRepo:        s.tokenRepo.WithTenant(organizationID),
+		recoveryRepo:     s.recoveryRepo.WithTenant(organizationID),
+		auditRepo:        s.auditRepo.WithTenant(organizationID),
+		apiKeyRepo:       s.apiKeyRepo.WithTenant(organizationID),
+		oidcRepo:         s.oidcRepo.WithTenant(organizationID),
+		notificationRepo: s.notificationRepo.WithTenant(organizationID),
+		authService:      s.authService.WithTenant(organizationID),
+		loginGuard:       s.loginGuard.WithTenant(organizationID),
+	}
+}
+
+func (s *accountService) ExportData(userID string) (*AccountExport, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = ""
+
+	tickets, err := s.ticketRepo.FindAllByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.tokenRepo.FindRefreshTokensByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	identities, err := s.oidcRepo.FindIdentitiesByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]AccountSession, 0, len(tokens))
+	for _, token := range tokens {
+		sessions = append(sessions, AccountSession{
+			SessionID: token.SessionID,
+			CreatedAt: token.CreatedAt,
+			ExpiresAt: token.ExpiresAt,
+			RevokedAt: token.RevokedAt,
+		})
+	}
+
+	return &AccountExport{
+		ExportedAt: time.Now(),
+		Profile:    AccountProfile{User: user, CreatedAt: user.CreatedAt},
+		Tickets:    tickets,
+		Sessions:   sessions,
+		Identities: identities,
+	}, nil
+}
+
+// DeleteAccount dipakai user untuk menghapus akunnya sendiri dengan konfirmasi password
+func (s *accountService) DeleteAccount(userID, password string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return errors.New("password is incorrect")
+	}
+
+	if err := ensureNotLastAdmin(s.userRepo, user); err != nil {
+		return err
+	}
+
+	return s.EraseUser(user)
+}
+
+// EraseUser mencabut semua sesi lalu menghapus PII user. Tiket tetap disimpan
+// untuk keperluan pembukuan dan hanya merujuk ke user yang sudah dianonimkan.
+func (s *accountService) EraseUser(user *entity.User) error {
+	userID := user.ID.String()
+
+	if err := s.authService.LogoutAll(userID); err != nil {
+		return err
+	}
+
+	if err := s.apiKeyRepo.RevokeByUserID(userID); err != nil {
+		return err
+	}
+
+	if err := s.oidcRepo.DeleteIdentitiesByUserID(userID); err != nil {
+		return err
+	}
+
+	if err := s.recoveryRepo.DeleteByUserID(userID); err != nil {
+		return err
+	}
+
+	if err := s.notificationRepo.DeleteByUserID(userID); err != nil {
+		return err
+	}
+
+	if err := s.loginGuard.RecordSuccess(user.Email); err != nil {
+		return err
+	}
+
+	if err := s.auditRepo.AnonymizeUser(userID, user.Email); err != nil {
+		return err
+	}
+
+	return s.userRepo.Delete(userID)
+}
+
+// GetNotifications mengembalikan log email notifikasi yang dikirim ke user, terbaru lebih dulu
+func (s *accountService) GetNotifications(userID string, params utils.PaginationParams) ([]entity.Notification, int64, error) {
+	return s.notificationRepo.FindAllByUserID(userID, params)
+}
+
+// WriteZip menulis export sebagai arsip ZIP dengan satu file JSON per bagian
+func (e *AccountExport) WriteZip(w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", e.Profile},
+		{"tickets.json", e.Tickets},
+		{"sessions.json", e.Sessions},
+		{"identities.json", e.Identities},
+	}
+
+	for _, file := range files {
+		writer, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: e.ExportedAt})
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
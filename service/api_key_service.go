@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// CreatedAPIKey berisi API key mentah yang hanya ditampilkan sekali saat dibuat
+type CreatedAPIKey struct {
+	*entity.APIKey
+	Key string `json:"key"`
+}
+
+type APIKeyService interface {
+	CreateAPIKey(userID, name string, scopes []string, expiresAt *time.Time) (*CreatedAPIKey, error)
+	GetAllAPIKeys(params utils.PaginationParams) ([]entity.APIKey, int64, error)
+	RevokeAPIKey(id string) error
+	WithTenant(organizationID string) APIKeyService
+	WithActor(actor Actor) APIKeyService
+}
+
+type apiKeyService struct {
+	apiKeyRepo   repository.APIKeyRepository
+	userRepo     repository.UserRepository
+	auditService AuditService
+	actor        Actor
+}
+
+func NewAPIKeyService(apiKeyRepo repository.APIKeyRepository, userRepo repository.UserRepository, auditService AuditService) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo:   apiKeyRepo,
+		userRepo:     userRepo,
+		auditService: auditService,
+	}
+}
+
+func (s *apiKeyService) WithTenant(organizationID string) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo:   s.apiKeyRepo.WithTenant(organizationID),
+		userRepo:     s.userRepo.WithTenant(organizationID),
+		auditService: s.auditService.WithTenant(organizationID),
+		actor:        s.actor,
+	}
+}
+
+// WithActor mengembalikan salinan service yang mencatat perubahan atas nama actor
+func (s *apiKeyService) WithActor(actor Actor) APIKeyService {
+	clone := *s
+	clone.actor = actor
+	return &clone
+}
+
+// CreateAPIKey membuat API key yang bertindak sebagai userID, atau sebagai admin
+// pembuatnya jika userID kosong. Hanya hash dari key yang disimpan.
+func (s *apiKeyService) CreateAPIKey(userID, name string, scopes []string, expiresAt *time.Time) (*CreatedAPIKey, error) {
+	if userID == "" {
+		userID = s.actor.UserID
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsSuspended() {
+		return nil, errors.New("user is suspended")
+	}
+
+	for _, scope := range scopes {
+		if !isValidAPIKeyScope(entity.APIKeyScope(scope)) {
+			return nil, fmt.Errorf("unknown scope: %s", scope)
+		}
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, errors.New("expiry must be in the future")
+	}
+
+	secret, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	rawKey := entity.APIKeyPrefix + secret
+
+	key := &entity.APIKey{
+		UserID:      user.ID,
+		CreatedByID: uuid.FromStringOrNil(s.actor.UserID),
+		Name:        name,
+		Prefix:      rawKey[:len(entity.APIKeyPrefix)+8],
+		KeyHash:     utils.HashToken(rawKey),
+		Scopes:      strings.Join(scopes, ","),
+		ExpiresAt:   expiresAt,
+	}
+
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(s.actor, "api_key.created", "api_key", key.ID.String(), nil, key)
+	return &CreatedAPIKey{APIKey: key, Key: rawKey}, nil
+}
+
+func (s *apiKeyService) GetAllAPIKeys(params utils.PaginationParams) ([]entity.APIKey, int64, error) {
+	return s.apiKeyRepo.FindAll(params)
+}
+
+func (s *apiKeyService) RevokeAPIKey(id string) error {
+	before, err := s.apiKeyRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := s.apiKeyRepo.Revoke(id)
+	if err != nil {
+		return err
+	}
+
+	if !revoked {
+		return errors.New("api key already revoked")
+	}
+
+	after, err := s.apiKeyRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	s.auditService.Record(s.actor, "api_key.revoked", "api_key", id, before, after)
+	return nil
+}
+
+func isValidAPIKeyScope(scope entity.APIKeyScope) bool {
+	for _, s := range entity.APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
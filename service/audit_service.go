@@ -0,0 +1,169 @@
+package service
+
+import (
+	"encoding/json"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"reflect"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// Actor menjelaskan siapa yang melakukan perubahan dan dari request mana
+type Actor struct {
+	UserID    string
+	APIKeyID  string
+	IP        string
+	RequestID string
+}
+
+// FieldChange menyimpan nilai sebuah field sebelum dan sesudah perubahan
+type FieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// auditIgnoredFields tidak pernah ditulis ke audit log
+var auditIgnoredFields = map[string]bool{
+	"password": true,
+}
+
+// auditRedactedValue menggantikan nilai field PII sehingga audit log hanya mencatat bahwa field itu berubah
+const auditRedactedValue = "[redacted]"
+
+// auditRedactedFields adalah field PII per entity type yang nilainya tidak disimpan di audit log
+var auditRedactedFields = map[string]map[string]bool{
+	"user": {"name": true, "email": true, "pending_email": true},
+}
+
+type AuditService interface {
+	Record(actor Actor, action, entityType, entityID string, before, after interface{})
+	GetAuditLogs(params utils.PaginationParams, filter repository.AuditLogFilter) ([]entity.AuditLog, int64, error)
+	WithTenant(organizationID string) AuditService
+}
+
+type auditService struct {
+	auditRepo repository.AuditLogRepository
+}
+
+func NewAuditService(auditRepo repository.AuditLogRepository) AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *auditService) WithTenant(organizationID string) AuditService {
+	return &auditService{
+		auditRepo: s.auditRepo.WithTenant(organizationID),
+	}
+}
+
+// Record menulis audit log beserta diff antara before dan after. Kegagalan hanya dicatat
+// di log aplikasi agar perubahan yang sudah tersimpan tidak ikut dianggap gagal.
+func (s *auditService) Record(actor Actor, action, entityType, entityID string, before, after interface{}) {
+	log := &entity.AuditLog{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		IP:         actor.IP,
+		RequestID:  actor.RequestID,
+	}
+
+	if actorID, err := uuid.FromString(actor.UserID); err == nil {
+		log.ActorID = &actorID
+	}
+
+	if apiKeyID, err := uuid.FromString(actor.APIKeyID); err == nil {
+		log.APIKeyID = &apiKeyID
+	}
+
+	changes, err := diffJSON(before, after)
+	if err != nil {
+		utils.Log.Errorf("Failed to build audit diff for %s %s: %v", action, entityID, err)
+	} else if len(changes) > 0 {
+		redactChanges(changes, auditRedactedFields[entityType])
+		data, err := json.Marshal(changes)
+		if err != nil {
+			utils.Log.Errorf("Failed to encode audit diff for %s %s: %v", action, entityID, err)
+		}
+		log.Changes = string(data)
+	}
+
+	if err := s.auditRepo.Create(log); err != nil {
+		utils.Log.Errorf("Failed to write audit log %s %s: %v", action, entityID, err)
+	}
+}
+
+func (s *auditService) GetAuditLogs(params utils.PaginationParams, filter repository.AuditLogFilter) ([]entity.AuditLog, int64, error) {
+	return s.auditRepo.FindAll(params, filter)
+}
+
+// diffJSON membandingkan dua nilai berdasarkan representasi JSON-nya dan hanya
+// mengembalikan field yang berubah. Nilai nil berarti entitas dibuat atau dihapus.
+func diffJSON(before, after interface{}) (map[string]FieldChange, error) {
+	beforeFields, err := toJSONFields(before)
+	if err != nil {
+		return nil, err
+	}
+
+	afterFields, err := toJSONFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]FieldChange{}
+	for key, value := range beforeFields {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		if newValue, ok := afterFields[key]; !ok || !reflect.DeepEqual(value, newValue) {
+			changes[key] = FieldChange{Before: value, After: afterFields[key]}
+		}
+	}
+
+	for key, value := range afterFields {
+		if auditIgnoredFields[key] {
+			continue
+		}
+		if _, ok := beforeFields[key]; !ok {
+			changes[key] = FieldChange{After: value}
+		}
+	}
+
+	return changes, nil
+}
+
+// redactChanges mengganti nilai field PII dengan auditRedactedValue. Nilai kosong tetap
+// dipertahankan agar terlihat apakah field itu diisi, diubah, atau dikosongkan.
+func redactChanges(changes map[string]FieldChange, fields map[string]bool) {
+	for key, change := range changes {
+		if !fields[key] {
+			continue
+		}
+		if change.Before != nil && change.Before != "" {
+			change.Before = auditRedactedValue
+		}
+		if change.After != nil && change.After != "" {
+			change.After = auditRedactedValue
+		}
+		changes[key] = change
+	}
+}
+
+func toJSONFields(value interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
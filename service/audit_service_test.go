@@ -0,0 +1,27 @@
+package service
+
+import "testing"
+
+func TestRedactChangesHidesPersonalData(t *testing.T) {
+	before := map[string]interface{}{"name": "Alice", "email": "alice@example.com", "pending_email": "", "language": "id"}
+	after := map[string]interface{}{"name": "Alice Smith", "email": "alice@example.com", "pending_email": "alice@new.example.com", "language": "en"}
+
+	changes, err := diffJSON(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	redactChanges(changes, auditRedactedFields["user"])
+
+	if change := changes["name"]; change.Before != auditRedactedValue || change.After != auditRedactedValue {
+		t.Fatalf("expected name values to be redacted, got %+v", change)
+	}
+	if change := changes["pending_email"]; change.Before != "" || change.After != auditRedactedValue {
+		t.Fatalf("expected pending_email to show it was set without its value, got %+v", change)
+	}
+	if _, ok := changes["email"]; ok {
+		t.Fatal("expected unchanged email not to be recorded")
+	}
+	if change := changes["language"]; change.Before != "id" || change.After != "en" {
+		t.Fatalf("expected non-personal fields to keep their values, got %+v", change)
+	}
+}
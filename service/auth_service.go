@@ -1,33 +1,128 @@
 package service
 
 import (
+	"crypto/rand"
+	"encoding/base32"
 	"errors"
 	"event-ticketing/config"
 	"event-ticketing/entity"
+	"event-ticketing/mailer"
 	"event-ticketing/middleware"
 	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/gofrs/uuid/v5"
 	"golang.org/x/crypto/bcrypt"
 )
 
+type TokenPair struct {
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+type LoginResult struct {
+	*TokenPair
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+}
+
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+const (
+	twoFactorChallengeExpiresIn = 5 * time.Minute
+	recoveryCodeCount           = 10
+)
+
 type AuthService interface {
 	Register(user *entity.User) error
-	Login(email, password string) (string, error)
+	Login(email, password, ip string) (*LoginResult, error)
+	CompleteLogin(user *entity.User) (*LoginResult, error)
+	VerifyTwoFactorLogin(challengeToken, code string) (*TokenPair, error)
+	RefreshToken(refreshToken string) (*TokenPair, error)
+	Logout(tokenID string) error
+	LogoutAll(userID string) error
+	ForgotPassword(email string) error
+	ResetPassword(token, password string) error
+	SendVerificationEmail(userID string) error
+	VerifyEmail(token string) error
+	UpdateProfile(userID, name, email, language string, eventRemindersOptOut *bool) (*entity.User, error)
+	ChangePassword(userID, tokenID, currentPassword, newPassword string) error
+	SetupTwoFactor(userID string) (*TwoFactorSetup, error)
+	EnableTwoFactor(userID, code string) ([]string, error)
+	DisableTwoFactor(userID, code string) error
 	GetUserByID(id string) (*entity.User, error)
+	WithTenant(organizationID string) AuthService
+	WithActor(actor Actor) AuthService
 }
 
 type authService struct {
-	userRepo repository.UserRepository
-	config   config.Config
+	userRepo      repository.UserRepository
+	tokenRepo     repository.TokenRepository
+	userTokenRepo repository.UserTokenRepository
+	recoveryRepo  repository.RecoveryCodeRepository
+	loginGuard    LoginGuard
+	auditService  AuditService
+	keyStore      *middleware.KeyStore
+	mailer        mailer.Mailer
+	config        config.Config
+	actor         Actor
 }
 
-func NewAuthService(userRepo repository.UserRepository, config config.Config) AuthService {
+func NewAuthService(
+	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+	userTokenRepo repository.UserTokenRepository,
+	recoveryRepo repository.RecoveryCodeRepository,
+	loginGuard LoginGuard,
+	auditService AuditService,
+	keyStore *middleware.KeyStore,
+	mailer mailer.Mailer,
+	config config.Config,
+) AuthService {
 	return &authService{
-		userRepo: userRepo,
-		config:   config,
+		userRepo:      userRepo,
+		tokenRepo:     tokenRepo,
+		userTokenRepo: userTokenRepo,
+		recoveryRepo:  recoveryRepo,
+		loginGuard:    loginGuard,
+		auditService:  auditService,
+		keyStore:      keyStore,
+		mailer:        mailer,
+		config:        config,
 	}
 }
 
+func (s *authService) WithTenant(organizationID string) AuthService {
+	return &authService{
+		userRepo:      s.userRepo.WithTenant(organizationID),
+		tokenRepo:     s.tokenRepo.WithTenant(organizationID),
+		userTokenRepo: s.userTokenRepo.WithTenant(organizationID),
+		recoveryRepo:  s.recoveryRepo.WithTenant(organizationID),
+		loginGuard:    s.loginGuard.WithTenant(organizationID),
+		auditService:  s.auditService.WithTenant(organizationID),
+		keyStore:      s.keyStore,
+		mailer:        s.mailer,
+		config:        s.config,
+		actor:         s.actor,
+	}
+}
+
+// WithActor mengembalikan salinan service yang mencatat perubahan atas nama actor
+func (s *authService) WithActor(actor Actor) AuthService {
+	clone := *s
+	clone.actor = actor
+	return &clone
+}
+
 func (s *authService) Register(user *entity.User) error {
 	existingUser, err := s.userRepo.FindByEmail(user.Email)
 	if err == nil && existingUser != nil {
@@ -38,28 +133,674 @@ func (s *authService) Register(user *entity.User) error {
 		user.Role = entity.UserRole
 	}
 
-	return s.userRepo.Create(user)
+	if user.Language == "" {
+		user.Language = entity.IndonesianLanguage
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return err
+	}
+
+	s.audit(user.ID.String(), "auth.registered", nil, user)
+
+	if err := s.sendVerificationEmail(user); err != nil {
+		utils.Log.Errorf("Failed to send verification email: %v", err)
+	}
+
+	return nil
 }
 
-func (s *authService) Login(email, password string) (string, error) {
+func (s *authService) Login(email, password, ip string) (*LoginResult, error) {
+	if err := s.loginGuard.Check(email, ip); err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.FindByEmail(email)
 	if err != nil {
-		return "", errors.New("invalid email or password")
+		// Tetap jalankan bcrypt agar waktu respons tidak membocorkan apakah email terdaftar
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return nil, s.loginFailed(email, ip)
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return "", errors.New("invalid email or password")
+		return nil, s.loginFailed(email, ip)
+	}
+
+	if err := s.loginGuard.RecordSuccess(email); err != nil {
+		return nil, err
+	}
+
+	return s.CompleteLogin(user)
+}
+
+// CompleteLogin membuat sesi untuk user yang sudah terautentikasi, baik lewat password
+// maupun identity provider. User dengan 2FA harus menyelesaikan langkah kedua lebih dulu.
+func (s *authService) CompleteLogin(user *entity.User) (*LoginResult, error) {
+	if user.IsSuspended() {
+		return nil, errors.New("account suspended")
 	}
 
-	token, err := middleware.GenerateToken(user.ID.String(), user.Email, user.Role, s.config.JWTSecret, s.config.JWTExpiresIn)
+	if user.IsTwoFactorEnabled() {
+		challengeToken, err := s.createUserToken(user, entity.TwoFactorChallenge, twoFactorChallengeExpiresIn)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{TwoFactorRequired: true, ChallengeToken: challengeToken}, nil
+	}
+
+	tokens, err := s.startSession(user, false)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return token, nil
+	return &LoginResult{TokenPair: tokens}, nil
+}
+
+func (s *authService) VerifyTwoFactorLogin(challengeToken, code string) (*TokenPair, error) {
+	userToken, err := s.consumeUserToken(entity.TwoFactorChallenge, challengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.FindByID(userToken.UserID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verifySecondFactor(user, code); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(user, true)
+}
+
+func (s *authService) RefreshToken(refreshToken string) (*TokenPair, error) {
+	token, err := s.tokenRepo.FindRefreshTokenByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	// Refresh token yang sudah dirotasi dipakai lagi, kemungkinan dicuri
+	if token.RevokedAt != nil {
+		if err := s.revokeSession(token.SessionID.String()); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("refresh token has been revoked")
+	}
+
+	if !token.IsActive() {
+		return nil, errors.New("refresh token has expired")
+	}
+
+	revoked, err := s.tokenRepo.RevokeRefreshToken(token.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		return nil, errors.New("refresh token has been revoked")
+	}
+
+	user, err := s.userRepo.FindByID(token.UserID.String())
+	if err != nil {
+		return nil, errors.New("invalid refresh token")
+	}
+
+	return s.issueTokens(user, token.SessionID, token.MFA)
+}
+
+func (s *authService) Logout(tokenID string) error {
+	token, err := s.tokenRepo.FindRefreshTokenByAccessTokenID(tokenID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.revokeSession(token.SessionID.String()); err != nil {
+		return err
+	}
+
+	s.audit(token.UserID.String(), "auth.logout", nil, nil)
+	return nil
+}
+
+func (s *authService) LogoutAll(userID string) error {
+	tokens, err := s.tokenRepo.FindRefreshTokensByUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.revokeTokens(tokens); err != nil {
+		return err
+	}
+
+	s.audit(userID, "auth.sessions_revoked", nil, nil)
+	return nil
+}
+
+func (s *authService) ForgotPassword(email string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		// Jangan beri tahu apakah email terdaftar
+		return nil
+	}
+
+	token, err := s.createUserToken(user, entity.PasswordResetToken, s.config.PasswordResetExpiresIn)
+	if err != nil {
+		return err
+	}
+
+	s.sendEmail(mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nUse the link below to reset your password. The link expires in %s.\n\n%s/reset-password?token=%s\n\nIf you did not request this, you can ignore this email.\n",
+			user.Name, s.config.PasswordResetExpiresIn, s.config.FrontendURL, token),
+	})
+	return nil
+}
+
+func (s *authService) ResetPassword(token, password string) error {
+	userToken, err := s.consumeUserToken(entity.PasswordResetToken, token)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(userToken.UserID.String(), password); err != nil {
+		return err
+	}
+
+	s.audit(userToken.UserID.String(), "auth.password_reset", nil, nil)
+
+	// Password baru membatalkan semua sesi yang sedang login
+	return s.LogoutAll(userToken.UserID.String())
+}
+
+func (s *authService) SendVerificationEmail(userID string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.IsEmailVerified() {
+		return errors.New("email already verified")
+	}
+
+	return s.sendVerificationEmail(user)
+}
+
+func (s *authService) VerifyEmail(token string) error {
+	userToken, err := s.consumeUserToken(entity.EmailVerificationToken, token)
+	if err == nil {
+		if err := s.userRepo.MarkEmailVerified(userToken.UserID.String()); err != nil {
+			return err
+		}
+
+		s.audit(userToken.UserID.String(), "auth.email_verified", nil, nil)
+		return nil
+	}
+
+	// Token juga bisa berasal dari permintaan ganti email
+	userToken, err = s.consumeUserToken(entity.EmailChangeToken, token)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(userToken.UserID.String())
+	if err != nil {
+		return err
+	}
+
+	if user.PendingEmail == "" {
+		return errors.New("no pending email change")
+	}
+
+	existingUser, err := s.userRepo.FindByEmail(user.PendingEmail)
+	if err == nil && existingUser != nil {
+		return errors.New("email already in use")
+	}
+
+	if err := s.userRepo.ConfirmEmailChange(user.ID.String(), user.PendingEmail); err != nil {
+		return err
+	}
+
+	s.audit(user.ID.String(), "auth.email_changed", map[string]string{"email": user.Email}, map[string]string{"email": user.PendingEmail})
+	return nil
+}
+
+// UpdateProfile mengubah nama, bahasa, dan pengaturan pengingat event secara langsung. Email baru disimpan sebagai pending
+// dan baru dipakai setelah diverifikasi lewat link yang dikirim ke alamat tersebut.
+func (s *authService) UpdateProfile(userID, name, email, language string, eventRemindersOptOut *bool) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *user
+	user.Name = name
+	if language != "" {
+		user.Language = entity.Language(language)
+	}
+	if eventRemindersOptOut != nil {
+		user.EventRemindersOptOut = *eventRemindersOptOut
+	}
+
+	emailChanged := email != "" && !strings.EqualFold(email, user.Email)
+	if emailChanged {
+		existingUser, err := s.userRepo.FindByEmail(email)
+		if err == nil && existingUser != nil {
+			return nil, errors.New("email already in use")
+		}
+		user.PendingEmail = email
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	s.audit(user.ID.String(), "auth.profile_updated", before, user)
+
+	if emailChanged {
+		token, err := s.createUserToken(user, entity.EmailChangeToken, s.config.EmailVerifyExpiresIn)
+		if err != nil {
+			return nil, err
+		}
+
+		s.sendEmail(mailer.Message{
+			To:      []string{email},
+			Subject: "Confirm your new email address",
+			Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your new email address by opening the link below. The link expires in %s.\n\n%s/verify-email?token=%s\n",
+				user.Name, s.config.EmailVerifyExpiresIn, s.config.FrontendURL, token),
+		})
+		s.sendEmail(mailer.Message{
+			To:      []string{user.Email},
+			Subject: "Your email address is being changed",
+			Body: fmt.Sprintf("Hi %s,\n\nA request was made to change the email address of your account to %s. If this was not you, please reset your password immediately.\n",
+				user.Name, email),
+		})
+	}
+
+	return user, nil
+}
+
+func (s *authService) ChangePassword(userID, tokenID, currentPassword, newPassword string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return errors.New("current password is incorrect")
+	}
+
+	if err := s.userRepo.UpdatePassword(userID, newPassword); err != nil {
+		return err
+	}
+
+	s.audit(userID, "auth.password_changed", nil, nil)
+
+	return s.revokeOtherSessions(userID, tokenID)
+}
+
+func (s *authService) SetupTwoFactor(userID string) (*TwoFactorSetup, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsTwoFactorEnabled() {
+		return nil, errors.New("two-factor authentication already enabled")
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret, err = utils.Encrypt(s.config.TwoFactorEncryptionKey, []byte(secret))
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPLastStep = 0
+
+	if err := s.userRepo.UpdateTwoFactor(user); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorSetup{
+		Secret: secret,
+		URI:    utils.TOTPURI(s.config.AppName, user.Email, secret),
+	}, nil
+}
+
+func (s *authService) EnableTwoFactor(userID, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsTwoFactorEnabled() {
+		return nil, errors.New("two-factor authentication already enabled")
+	}
+
+	if user.TOTPSecret == "" {
+		return nil, errors.New("two-factor setup has not been started")
+	}
+
+	if err := s.verifyTOTP(user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recoveryRepo.ReplaceForUser(user.ID, hashes); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.TwoFactorEnabledAt = &now
+	if err := s.userRepo.UpdateTwoFactor(user); err != nil {
+		return nil, err
+	}
+
+	s.audit(userID, "auth.two_factor_enabled", nil, nil)
+
+	// Sesi lama belum melewati 2FA, jadi user harus login ulang
+	if err := s.LogoutAll(userID); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (s *authService) DisableTwoFactor(userID, code string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsTwoFactorEnabled() {
+		return errors.New("two-factor authentication is not enabled")
+	}
+
+	if err := s.verifySecondFactor(user, code); err != nil {
+		return err
+	}
+
+	if err := s.recoveryRepo.DeleteByUserID(userID); err != nil {
+		return err
+	}
+
+	user.TOTPSecret = ""
+	user.TOTPLastStep = 0
+	user.TwoFactorEnabledAt = nil
+	if err := s.userRepo.UpdateTwoFactor(user); err != nil {
+		return err
+	}
+
+	s.audit(userID, "auth.two_factor_disabled", nil, nil)
+	return nil
 }
 
 func (s *authService) GetUserByID(id string) (*entity.User, error) {
 	return s.userRepo.FindByID(id)
 }
+
+// verifySecondFactor menerima kode TOTP atau salah satu recovery code
+func (s *authService) verifySecondFactor(user *entity.User, code string) error {
+	if err := s.verifyTOTP(user, code); err == nil {
+		return nil
+	}
+
+	used, err := s.recoveryRepo.Use(user.ID.String(), utils.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return errors.New("invalid two-factor code")
+	}
+	return nil
+}
+
+func (s *authService) verifyTOTP(user *entity.User, code string) error {
+	secret, err := utils.Decrypt(s.config.TwoFactorEncryptionKey, user.TOTPSecret)
+	if err != nil {
+		return err
+	}
+
+	step, ok := utils.ValidateTOTP(string(secret), code, time.Now())
+	if !ok || step <= user.TOTPLastStep {
+		return errors.New("invalid two-factor code")
+	}
+
+	// Simpan step terakhir agar kode yang sama tidak bisa dipakai ulang
+	user.TOTPLastStep = step
+	return s.userRepo.UpdateTwoFactor(user)
+}
+
+func (s *authService) loginFailed(email, ip string) error {
+	if err := s.loginGuard.RecordFailure(email, ip); err != nil {
+		utils.Log.Errorf("Failed to record login failure: %v", err)
+	}
+	return errors.New("invalid email or password")
+}
+
+func (s *authService) startSession(user *entity.User, mfa bool) (*TokenPair, error) {
+	sessionID, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.issueTokens(user, sessionID, mfa)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(user.ID.String(), "auth.login", nil, map[string]interface{}{"session_id": sessionID, "mfa": mfa})
+	return tokens, nil
+}
+
+// audit mencatat perubahan pada akun user. Request tanpa login, seperti register
+// atau reset password, dicatat atas nama user itu sendiri.
+func (s *authService) audit(userID, action string, before, after interface{}) {
+	actor := s.actor
+	if actor.UserID == "" {
+		actor.UserID = userID
+	}
+	s.auditService.Record(actor, action, "user", userID, before, after)
+}
+
+func (s *authService) sendVerificationEmail(user *entity.User) error {
+	token, err := s.createUserToken(user, entity.EmailVerificationToken, s.config.EmailVerifyExpiresIn)
+	if err != nil {
+		return err
+	}
+
+	s.sendEmail(mailer.Message{
+		To:      []string{user.Email},
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below. The link expires in %s.\n\n%s/verify-email?token=%s\n",
+			user.Name, s.config.EmailVerifyExpiresIn, s.config.FrontendURL, token),
+	})
+	return nil
+}
+
+// createUserToken membuat token sekali pakai dan membatalkan token lama dengan tujuan yang sama
+func (s *authService) createUserToken(user *entity.User, purpose entity.UserTokenPurpose, expiresIn time.Duration) (string, error) {
+	if err := s.userTokenRepo.InvalidateByUserID(user.ID.String(), purpose); err != nil {
+		return "", err
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.userTokenRepo.Create(&entity.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(expiresIn),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (s *authService) consumeUserToken(purpose entity.UserTokenPurpose, token string) (*entity.UserToken, error) {
+	userToken, err := s.userTokenRepo.FindByHash(purpose, utils.HashToken(token))
+	if err != nil || !userToken.IsValid() {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	used, err := s.userTokenRepo.MarkUsed(userToken.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if !used {
+		return nil, errors.New("invalid or expired token")
+	}
+
+	return userToken, nil
+}
+
+// sendEmail mengirim email di background agar waktu respons tidak bergantung pada SMTP
+func (s *authService) sendEmail(message mailer.Message) {
+	go func() {
+		if err := s.mailer.Send(message); err != nil {
+			utils.Log.Errorf("Failed to send email %q: %v", message.Subject, err)
+		}
+	}()
+}
+
+// issueTokens membuat access token baru beserta refresh token untuk sesi yang sama
+func (s *authService) issueTokens(user *entity.User, sessionID uuid.UUID, mfa bool) (*TokenPair, error) {
+	if user.IsSuspended() {
+		return nil, errors.New("account suspended")
+	}
+
+	tokenID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	pair := &TokenPair{
+		ExpiresAt:        now.Add(s.config.JWTExpiresIn),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: now.Add(s.config.RefreshTokenExpiresIn),
+	}
+
+	pair.Token, err = middleware.GenerateToken(s.keyStore, tokenID.String(), user.ID.String(), user.OrganizationID.String(), user.Email, user.Role, mfa, s.config.JWTExpiresIn)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tokenRepo.CreateRefreshToken(&entity.RefreshToken{
+		UserID:          user.ID,
+		SessionID:       sessionID,
+		TokenHash:       utils.HashToken(refreshToken),
+		AccessTokenID:   tokenID.String(),
+		AccessExpiresAt: pair.ExpiresAt,
+		ExpiresAt:       pair.RefreshExpiresAt,
+		MFA:             mfa,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pair, nil
+}
+
+// revokeOtherSessions mencabut semua sesi user kecuali sesi yang sedang dipakai
+func (s *authService) revokeOtherSessions(userID, tokenID string) error {
+	current, err := s.tokenRepo.FindRefreshTokenByAccessTokenID(tokenID)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := s.tokenRepo.FindRefreshTokensByUserID(userID)
+	if err != nil {
+		return err
+	}
+
+	others := make([]entity.RefreshToken, 0, len(tokens))
+	for _, token := range tokens {
+		if token.SessionID != current.SessionID {
+			others = append(others, token)
+		}
+	}
+
+	return s.revokeTokens(others)
+}
+
+func (s *authService) revokeSession(sessionID string) error {
+	tokens, err := s.tokenRepo.FindRefreshTokensBySessionID(sessionID)
+	if err != nil {
+		return err
+	}
+
+	return s.revokeTokens(tokens)
+}
+
+// revokeTokens mencabut refresh token dan memasukkan access token yang masih berlaku ke denylist
+func (s *authService) revokeTokens(tokens []entity.RefreshToken) error {
+	now := time.Now()
+	for _, token := range tokens {
+		if token.AccessExpiresAt.After(now) {
+			if err := s.tokenRepo.RevokeAccessToken(token.AccessTokenID, token.AccessExpiresAt); err != nil {
+				return err
+			}
+		}
+
+		if token.RevokedAt == nil {
+			if _, err := s.tokenRepo.RevokeRefreshToken(token.ID.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// generateRecoveryCodes menghasilkan recovery code untuk ditampilkan sekali beserta hash-nya
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		buf := make([]byte, 6)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf))
+		codes[i] = code[:5] + "-" + code[5:]
+		hashes[i] = utils.HashToken(normalizeRecoveryCode(codes[i]))
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+var (
+	dummyHash     []byte
+	dummyHashOnce sync.Once
+)
+
+func dummyPasswordHash() []byte {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	})
+	return dummyHash
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"event-ticketing/broker"
+	"event-ticketing/domain"
+	"event-ticketing/entity"
+	"event-ticketing/notification"
+	"event-ticketing/utils"
+)
+
+// domainEventHandlers menjalankan efek samping domain event setelah transaksinya commit:
+// webhook partner, update live lewat broker, dan email ke pemegang tiket.
+//
+// Webhook dan email hanya disimpan untuk dikirim worker, dan update live dipublish paling
+// akhir karena kegagalannya hanya dicatat di log. Jika webhook atau email gagal disimpan, relay mengirim
+// ulang pesan tanpa update live yang dobel; pengiriman webhook yang sudah tercatat untuk
+// pesan yang sama diabaikan, dan setiap handler menyimpan email dalam satu insert.
+type domainEventHandlers struct {
+	broker         broker.Broker
+	notifier       notification.Notifier
+	webhookService WebhookService
+	reportService  ReportService
+}
+
+// RegisterDomainEventHandlers mendaftarkan semua efek samping domain event ke bus
+func RegisterDomainEventHandlers(bus domain.Bus, broker broker.Broker, notifier notification.Notifier, webhookService WebhookService, reportService ReportService) {
+	h := &domainEventHandlers{broker: broker, notifier: notifier, webhookService: webhookService, reportService: reportService}
+
+	bus.Subscribe(domain.TicketPurchasedType, h.ticketPurchased)
+	bus.Subscribe(domain.TicketCancelledType, h.ticketCancelled)
+	bus.Subscribe(domain.TicketCheckedInType, h.ticketCheckedIn)
+	bus.Subscribe(domain.EventCreatedType, h.eventCreated)
+	bus.Subscribe(domain.EventUpdatedType, h.eventUpdated)
+	bus.Subscribe(domain.EventDeletedType, h.eventDeleted)
+}
+
+func (h *domainEventHandlers) dispatchWebhook(envelope domain.Envelope, eventType entity.WebhookEventType, data interface{}) error {
+	return h.webhookService.WithTenant(envelope.OrganizationID.String()).Dispatch(envelope.ID, envelope.OccurredAt, eventType, data)
+}
+
+// publishReportTotals mengirim total laporan event terkini ke subscriber stream live.
+// Data tiket dan event tidak ikut dikirim karena berisi kode booking dan ID user.
+func (h *domainEventHandlers) publishReportTotals(envelope domain.Envelope, eventID, messageType string) {
+	totals, err := h.reportService.WithTenant(envelope.OrganizationID.String()).GenerateEventTotals(eventID)
+	if err != nil {
+		utils.Log.Errorf("Failed to generate report totals for event %s: %v", eventID, err)
+		return
+	}
+
+	totals.MessageID = envelope.ID
+	publishEventChange(h.broker, eventID, messageType, totals)
+}
+
+// envelopeTicket mengembalikan organisasi tiket yang tidak ikut disimpan di payload JSON
+func envelopeTicket(envelope domain.Envelope, ticket *entity.Ticket) *entity.Ticket {
+	ticket.OrganizationID = envelope.OrganizationID
+	return ticket
+}
+
+func (h *domainEventHandlers) ticketPurchased(envelope domain.Envelope) error {
+	ticket := envelopeTicket(envelope, &envelope.Event.(*domain.TicketPurchased).Ticket)
+
+	if err := h.dispatchWebhook(envelope, entity.TicketPurchasedWebhook, ticket); err != nil {
+		return err
+	}
+
+	if err := h.notifier.TicketPurchased(ticket); err != nil {
+		return err
+	}
+
+	h.publishReportTotals(envelope, ticket.EventID.String(), "ticket.purchased")
+	return nil
+}
+
+func (h *domainEventHandlers) ticketCancelled(envelope domain.Envelope) error {
+	ticket := envelopeTicket(envelope, &envelope.Event.(*domain.TicketCancelled).Ticket)
+
+	if err := h.dispatchWebhook(envelope, entity.TicketCancelledWebhook, ticket); err != nil {
+		return err
+	}
+
+	if err := h.notifier.TicketCancelled(ticket); err != nil {
+		return err
+	}
+
+	h.publishReportTotals(envelope, ticket.EventID.String(), "ticket.cancelled")
+	return nil
+}
+
+func (h *domainEventHandlers) ticketCheckedIn(envelope domain.Envelope) error {
+	ticket := envelopeTicket(envelope, &envelope.Event.(*domain.TicketCheckedIn).Ticket)
+
+	if err := h.dispatchWebhook(envelope, entity.TicketCheckedInWebhook, ticket); err != nil {
+		return err
+	}
+
+	h.publishReportTotals(envelope, ticket.EventID.String(), "ticket.checked_in")
+	return nil
+}
+
+func (h *domainEventHandlers) eventCreated(envelope domain.Envelope) error {
+	return h.dispatchWebhook(envelope, entity.EventCreatedWebhook, &envelope.Event.(*domain.EventCreated).Event)
+}
+
+func (h *domainEventHandlers) eventUpdated(envelope domain.Envelope) error {
+	updated := envelope.Event.(*domain.EventUpdated)
+
+	if err := h.dispatchWebhook(envelope, entity.EventUpdatedWebhook, &updated.After); err != nil {
+		return err
+	}
+
+	if err := h.notifier.EventChanged(&updated.Before, &updated.After); err != nil {
+		return err
+	}
+
+	h.publishReportTotals(envelope, updated.After.ID.String(), "event.updated")
+	return nil
+}
+
+func (h *domainEventHandlers) eventDeleted(envelope domain.Envelope) error {
+	event := &envelope.Event.(*domain.EventDeleted).Event
+
+	if err := h.dispatchWebhook(envelope, entity.EventDeletedWebhook, event); err != nil {
+		return err
+	}
+
+	return h.notifier.EventCancelled(event)
+}
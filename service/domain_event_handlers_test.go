@@ -0,0 +1,67 @@
+package service
+
+import (
+	"encoding/json"
+	"event-ticketing/broker"
+	"event-ticketing/domain"
+	"event-ticketing/entity"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func TestTicketPurchasedPublishesReportTotalsWithoutTicketData(t *testing.T) {
+	bus := broker.NewMemoryBroker()
+	defer bus.Close()
+
+	ticket := entity.Ticket{
+		BaseEntity:  entity.BaseEntity{ID: uuid.Must(uuid.NewV7())},
+		EventID:     uuid.Must(uuid.NewV7()),
+		UserID:      uuid.Must(uuid.NewV7()),
+		BookingCode: "TKT-SECRET",
+		Price:       100,
+	}
+	sub, err := bus.Subscribe(EventTopic(ticket.EventID.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	h := &domainEventHandlers{
+		broker:         bus,
+		notifier:       &fakeNotifier{},
+		webhookService: &fakeWebhookService{},
+		reportService:  &fakeReportService{totals: EventReportTotals{TotalTickets: 10, SoldTickets: 3, Revenue: 300}},
+	}
+	envelope := domain.Envelope{ID: uuid.Must(uuid.NewV7()), OrganizationID: uuid.Must(uuid.NewV7()), Event: &domain.TicketPurchased{Ticket: ticket}}
+	if err := h.ticketPurchased(envelope); err != nil {
+		t.Fatal(err)
+	}
+
+	var message broker.Message
+	select {
+	case message = <-sub.Messages():
+	case <-time.After(time.Second):
+		t.Fatal("no message published")
+	}
+
+	totals, ok := message.Data.(*EventReportTotals)
+	if !ok {
+		t.Fatalf("published %T, want *EventReportTotals", message.Data)
+	}
+	if message.Type != "ticket.purchased" || totals.MessageID != envelope.ID || totals.SoldTickets != 3 || totals.Revenue != 300 {
+		t.Fatalf("published %s %+v, want ticket.purchased totals for message %s", message.Type, totals, envelope.ID)
+	}
+
+	payload, err := json.Marshal(message.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{ticket.BookingCode, ticket.UserID.String(), ticket.ID.String()} {
+		if strings.Contains(string(payload), leaked) {
+			t.Errorf("stream payload %s contains ticket data %q", payload, leaked)
+		}
+	}
+}
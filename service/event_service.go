@@ -2,32 +2,76 @@ package service
 
 import (
 	"errors"
+	"event-ticketing/broker"
+	"event-ticketing/domain"
 	"event-ticketing/entity"
 	"event-ticketing/repository"
 	"event-ticketing/utils"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type EventService interface {
 	CreateEvent(event *entity.Event) error
 	GetEventByID(id string) (*entity.Event, error)
 	GetAllEvents(params utils.PaginationParams, keyword, status, startDate, endDate string) ([]entity.Event, int64, error)
-	UpdateEvent(event *entity.Event) (*entity.Event, error)
+	UpdateEvent(event *entity.Event, maxTicketsPerUser *int, queueEnabled *bool, queueAdmitRate *int) (*entity.Event, error)
 	DeleteEvent(id string) error
+	WithTenant(organizationID string) EventService
+	WithActor(actor Actor) EventService
 }
 
 type eventService struct {
-	eventRepo  repository.EventRepository
-	ticketRepo repository.TicketRepository
+	db           *gorm.DB
+	eventRepo    repository.EventRepository
+	ticketRepo   repository.TicketRepository
+	outboxRepo   repository.OutboxRepository
+	auditService AuditService
+	actor        Actor
+}
+
+func NewEventService(db *gorm.DB, eventRepo repository.EventRepository, ticketRepo repository.TicketRepository, outboxRepo repository.OutboxRepository, auditService AuditService) EventService {
+	return &eventService{
+		db:           db,
+		eventRepo:    eventRepo,
+		ticketRepo:   ticketRepo,
+		outboxRepo:   outboxRepo,
+		auditService: auditService,
+	}
 }
 
-func NewEventService(eventRepo repository.EventRepository, ticketRepo repository.TicketRepository) EventService {
+func (s *eventService) WithTenant(organizationID string) EventService {
 	return &eventService{
-		eventRepo:  eventRepo,
-		ticketRepo: ticketRepo,
+		db:           s.db,
+		eventRepo:    s.eventRepo.WithTenant(organizationID),
+		ticketRepo:   s.ticketRepo.WithTenant(organizationID),
+		outboxRepo:   s.outboxRepo.WithTenant(organizationID),
+		auditService: s.auditService.WithTenant(organizationID),
+		actor:        s.actor,
 	}
 }
 
+// EventTopic adalah topic broker untuk perubahan tiket dan data sebuah event
+func EventTopic(eventID string) string {
+	return "events." + eventID
+}
+
+// publishEventChange memberi tahu subscriber bahwa data sebuah event berubah.
+// Kegagalan hanya dicatat karena perubahan di database sudah tersimpan.
+func publishEventChange(b broker.Broker, eventID, messageType string, data interface{}) {
+	if err := b.Publish(EventTopic(eventID), messageType, data); err != nil {
+		utils.Log.Errorf("Failed to publish %s for event %s: %v", messageType, eventID, err)
+	}
+}
+
+// WithActor mengembalikan salinan service yang mencatat perubahan atas nama actor
+func (s *eventService) WithActor(actor Actor) EventService {
+	clone := *s
+	clone.actor = actor
+	return &clone
+}
+
 func (s *eventService) CreateEvent(event *entity.Event) error {
 	existingEvent, err := s.eventRepo.FindByName(event.Name)
 	if err == nil && existingEvent != nil {
@@ -42,6 +86,14 @@ func (s *eventService) CreateEvent(event *entity.Event) error {
 		return errors.New("event price cannot be less than 0")
 	}
 
+	if event.MaxTicketsPerUser < 0 {
+		return errors.New("max tickets per user cannot be less than 0")
+	}
+
+	if event.QueueEnabled && event.QueueAdmitRate < 1 {
+		return errors.New("queue admit rate must be at least 1 when queue is enabled")
+	}
+
 	if event.StartDate.Before(time.Now()) {
 		return errors.New("event start date must be in the future")
 	}
@@ -52,7 +104,19 @@ func (s *eventService) CreateEvent(event *entity.Event) error {
 
 	event.Status = entity.ActiveEvent
 
-	return s.eventRepo.Create(event)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.eventRepo.WithTx(tx).Create(event); err != nil {
+			return err
+		}
+
+		return recordDomainEvent(s.outboxRepo.WithTx(tx), &domain.EventCreated{Event: *event})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.auditService.Record(s.actor, "event.created", "event", event.ID.String(), nil, event)
+	return nil
 }
 
 func (s *eventService) GetEventByID(id string) (*entity.Event, error) {
@@ -63,7 +127,9 @@ func (s *eventService) GetAllEvents(params utils.PaginationParams, keyword, stat
 	return s.eventRepo.FindAll(params, keyword, status, startDate, endDate)
 }
 
-func (s *eventService) UpdateEvent(event *entity.Event) (*entity.Event, error) {
+// UpdateEvent mengganti data event. Batas tiket per user dan pengaturan antrean yang nil
+// tidak mengubah nilai yang tersimpan.
+func (s *eventService) UpdateEvent(event *entity.Event, maxTicketsPerUser *int, queueEnabled *bool, queueAdmitRate *int) (*entity.Event, error) {
 	existingEvent, err := s.eventRepo.FindByID(event.ID.String())
 	if err != nil {
 		return nil, err
@@ -80,15 +146,26 @@ func (s *eventService) UpdateEvent(event *entity.Event) (*entity.Event, error) {
 		}
 	}
 
-	if event.Capacity < existingEvent.TicketsSold {
-		return nil, errors.New("cannot reduce capacity below sold tickets count")
-	}
+	before := *existingEvent
 
 	existingEvent.Name = event.Name
 	existingEvent.Description = event.Description
 	existingEvent.Capacity = event.Capacity
 	existingEvent.Price = event.Price
 	existingEvent.Location = event.Location
+	if maxTicketsPerUser != nil {
+		existingEvent.MaxTicketsPerUser = *maxTicketsPerUser
+	}
+	if queueEnabled != nil {
+		existingEvent.QueueEnabled = *queueEnabled
+	}
+	if queueAdmitRate != nil {
+		existingEvent.QueueAdmitRate = *queueAdmitRate
+	}
+
+	if existingEvent.QueueEnabled && existingEvent.QueueAdmitRate < 1 {
+		return nil, errors.New("queue admit rate must be at least 1 when queue is enabled")
+	}
 
 	if !event.StartDate.Equal(existingEvent.StartDate) && event.StartDate.Before(time.Now()) {
 		return nil, errors.New("event start date must be in the future")
@@ -101,11 +178,31 @@ func (s *eventService) UpdateEvent(event *entity.Event) (*entity.Event, error) {
 	existingEvent.StartDate = event.StartDate
 	existingEvent.EndDate = event.EndDate
 
-	err = s.eventRepo.Update(existingEvent)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		eventRepo := s.eventRepo.WithTx(tx)
+
+		// Kapasitas dicek terhadap baris yang dikunci agar pembelian yang commit bersamaan
+		// tidak membuat tickets_sold melebihi kapasitas baru
+		locked, err := eventRepo.FindByIDForUpdate(existingEvent.ID.String())
+		if err != nil {
+			return err
+		}
+		if existingEvent.Capacity < locked.TicketsSold {
+			return errors.New("cannot reduce capacity below sold tickets count")
+		}
+
+		if err := eventRepo.Update(existingEvent); err != nil {
+			return err
+		}
+
+		return recordDomainEvent(s.outboxRepo.WithTx(tx), &domain.EventUpdated{Before: before, After: *existingEvent})
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	s.auditService.Record(s.actor, "event.updated", "event", existingEvent.ID.String(), before, existingEvent)
+
 	return s.eventRepo.FindByID(event.ID.String())
 }
 
@@ -119,5 +216,17 @@ func (s *eventService) DeleteEvent(id string) error {
 		return errors.New("cannot delete ongoing or completed event")
 	}
 
-	return s.eventRepo.Delete(id)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.eventRepo.WithTx(tx).Delete(id); err != nil {
+			return err
+		}
+
+		return recordDomainEvent(s.outboxRepo.WithTx(tx), &domain.EventDeleted{Event: *event})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.auditService.Record(s.actor, "event.deleted", "event", id, event, nil)
+	return nil
 }
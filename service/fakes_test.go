@@ -0,0 +1,301 @@
+package service
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"event-ticketing/notification"
+	"event-ticketing/repository"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// Fake repository hanya mengimplementasikan method yang dipakai test. Method lain
+// dari interface yang di-embed akan panic jika terpanggil.
+
+type fakeUserRepository struct {
+	repository.UserRepository
+
+	mu    sync.Mutex
+	users map[uuid.UUID]*entity.User
+}
+
+func newFakeUserRepository() *fakeUserRepository {
+	return &fakeUserRepository{users: map[uuid.UUID]*entity.User{}}
+}
+
+func (r *fakeUserRepository) WithTenant(organizationID string) repository.UserRepository {
+	return r
+}
+
+func (r *fakeUserRepository) Create(user *entity.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user.ID = uuid.Must(uuid.NewV7())
+	user.CreatedAt = time.Now()
+	copied := *user
+	r.users[user.ID] = &copied
+	return nil
+}
+
+func (r *fakeUserRepository) FindByID(id string) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[uuid.FromStringOrNil(id)]
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepository) FindByEmail(email string) (*entity.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("user not found")
+}
+
+func (r *fakeUserRepository) MarkEmailVerified(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[uuid.FromStringOrNil(id)]
+	if !ok {
+		return errors.New("user not found")
+	}
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	return nil
+}
+
+type fakeOIDCRepository struct {
+	repository.OIDCRepository
+
+	mu             sync.Mutex
+	organizationID uuid.UUID
+	states         map[string]*entity.OIDCState
+	identities     []entity.UserIdentity
+}
+
+func newFakeOIDCRepository(organizationID uuid.UUID) *fakeOIDCRepository {
+	return &fakeOIDCRepository{organizationID: organizationID, states: map[string]*entity.OIDCState{}}
+}
+
+func (r *fakeOIDCRepository) WithTenant(organizationID string) repository.OIDCRepository {
+	return r
+}
+
+func (r *fakeOIDCRepository) CreateState(state *entity.OIDCState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state.OrganizationID = r.organizationID
+	copied := *state
+	r.states[state.Provider+"|"+state.StateHash] = &copied
+	return nil
+}
+
+func (r *fakeOIDCRepository) ConsumeState(provider, hash string) (*entity.OIDCState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, ok := r.states[provider+"|"+hash]
+	if !ok {
+		return nil, errors.New("invalid or expired state")
+	}
+	delete(r.states, provider+"|"+hash)
+	return state, nil
+}
+
+// only mengembalikan satu-satunya state yang tersimpan agar test bisa mengubahnya
+func (r *fakeOIDCRepository) only() *entity.OIDCState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, state := range r.states {
+		return state
+	}
+	return nil
+}
+
+func (r *fakeOIDCRepository) FindIdentity(provider, subject string) (*entity.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return &identity, nil
+		}
+	}
+	return nil, errors.New("identity not found")
+}
+
+func (r *fakeOIDCRepository) CreateIdentity(identity *entity.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+// fakeAuthService menerbitkan "token" berisi ID user tanpa membuat sesi sungguhan
+type fakeAuthService struct {
+	AuthService
+}
+
+func (s *fakeAuthService) WithTenant(organizationID string) AuthService {
+	return s
+}
+
+func (s *fakeAuthService) WithActor(actor Actor) AuthService {
+	return s
+}
+
+func (s *fakeAuthService) LogoutAll(userID string) error {
+	return nil
+}
+
+func (s *fakeAuthService) CompleteLogin(user *entity.User) (*LoginResult, error) {
+	return &LoginResult{TokenPair: &TokenPair{Token: user.ID.String()}}, nil
+}
+
+func (r *fakeUserRepository) UpdateRole(id string, role entity.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[uuid.FromStringOrNil(id)]
+	if !ok {
+		return errors.New("user not found")
+	}
+	user.Role = role
+	return nil
+}
+
+func (r *fakeUserRepository) UpdateSuspendedAt(id string, suspendedAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[uuid.FromStringOrNil(id)]
+	if !ok {
+		return errors.New("user not found")
+	}
+	user.SuspendedAt = suspendedAt
+	return nil
+}
+
+func (r *fakeUserRepository) CountActiveByRole(role entity.Role) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var count int64
+	for _, user := range r.users {
+		if user.Role == role && !user.IsSuspended() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+type auditRecord struct {
+	actor      Actor
+	action     string
+	entityType string
+	entityID   string
+	changes    map[string]FieldChange
+}
+
+// fakeAuditService menyimpan diff yang akan ditulis ke audit log
+type fakeAuditService struct {
+	AuditService
+
+	mu      sync.Mutex
+	records []auditRecord
+}
+
+func (s *fakeAuditService) WithTenant(organizationID string) AuditService {
+	return s
+}
+
+func (s *fakeAuditService) Record(actor Actor, action, entityType, entityID string, before, after interface{}) {
+	changes, err := diffJSON(before, after)
+	if err != nil {
+		panic(err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, auditRecord{actor, action, entityType, entityID, changes})
+}
+
+func (s *fakeAuditService) last() auditRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.records) == 0 {
+		return auditRecord{}
+	}
+	return s.records[len(s.records)-1]
+}
+
+type fakeWebhookRepository struct {
+	repository.WebhookRepository
+
+	subscriptions []*entity.WebhookSubscription
+}
+
+func (r *fakeWebhookRepository) WithTenant(organizationID string) repository.WebhookRepository {
+	return r
+}
+
+func (r *fakeWebhookRepository) CreateSubscription(subscription *entity.WebhookSubscription) error {
+	subscription.ID = uuid.Must(uuid.NewV7())
+	r.subscriptions = append(r.subscriptions, subscription)
+	return nil
+}
+
+type fakeWebhookService struct {
+	WebhookService
+}
+
+func (s *fakeWebhookService) WithTenant(organizationID string) WebhookService {
+	return s
+}
+
+func (s *fakeWebhookService) Dispatch(messageID uuid.UUID, occurredAt time.Time, eventType entity.WebhookEventType, data interface{}) error {
+	return nil
+}
+
+type fakeNotifier struct {
+	notification.Notifier
+}
+
+func (n *fakeNotifier) TicketPurchased(ticket *entity.Ticket) error {
+	return nil
+}
+
+type fakeReportService struct {
+	ReportService
+
+	totals EventReportTotals
+}
+
+func (s *fakeReportService) WithTenant(organizationID string) ReportService {
+	return s
+}
+
+func (s *fakeReportService) GenerateEventTotals(eventID string) (*EventReportTotals, error) {
+	totals := s.totals
+	totals.GeneratedAt = time.Now()
+	return &totals, nil
+}
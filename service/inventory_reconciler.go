@@ -0,0 +1,64 @@
+package service
+
+import (
+	"event-ticketing/config"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"time"
+)
+
+// InventoryReconciler memeriksa counter tickets_sold pada event terhadap jumlah tiket
+// terjual sebenarnya. Selisih dicatat di log lalu diperbaiki di bawah lock baris event.
+type InventoryReconciler interface {
+	Reconcile() ([]repository.TicketsSoldDrift, error)
+	Start()
+}
+
+type inventoryReconciler struct {
+	eventRepo repository.EventRepository
+	config    config.Config
+}
+
+func NewInventoryReconciler(eventRepo repository.EventRepository, config config.Config) InventoryReconciler {
+	return &inventoryReconciler{
+		eventRepo: eventRepo,
+		config:    config,
+	}
+}
+
+func (r *inventoryReconciler) Reconcile() ([]repository.TicketsSoldDrift, error) {
+	drifts, err := r.eventRepo.FindTicketsSoldDrift()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, drift := range drifts {
+		utils.Log.Warnf("Tickets sold drift on event %s (organization %s): counter %d, actual %d",
+			drift.EventID, drift.OrganizationID, drift.Stored, drift.Actual)
+
+		eventRepo := r.eventRepo.WithTenant(drift.OrganizationID.String())
+		if err := eventRepo.ReconcileTicketsSold(drift.EventID.String()); err != nil {
+			return drifts, err
+		}
+	}
+
+	return drifts, nil
+}
+
+// Start menjalankan rekonsiliasi berkala di background
+func (r *inventoryReconciler) Start() {
+	if r.config.InventoryReconcileInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(r.config.InventoryReconcileInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := r.Reconcile(); err != nil {
+				utils.Log.Errorf("Failed to reconcile tickets sold: %v", err)
+			}
+		}
+	}()
+}
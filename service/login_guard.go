@@ -0,0 +1,142 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"event-ticketing/config"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"strings"
+	"time"
+)
+
+const (
+	loginBackoffBase      = time.Second
+	maxLoginLockout       = 24 * time.Hour
+	accountLockedAction   = "auth.account_locked"
+	ipLockedAction        = "auth.ip_locked"
+	accountUnlockedAction = "auth.account_unlocked"
+)
+
+var ErrTooManyAttempts = errors.New("too many login attempts, please try again later")
+
+// LoginGuard membatasi percobaan login per email dan per IP dengan exponential backoff
+type LoginGuard interface {
+	Check(email, ip string) error
+	RecordFailure(email, ip string) error
+	RecordSuccess(email string) error
+	Unlock(email string) error
+	WithTenant(organizationID string) LoginGuard
+}
+
+type loginGuard struct {
+	throttleRepo repository.LoginThrottleRepository
+	auditRepo    repository.AuditLogRepository
+	config       config.Config
+}
+
+func NewLoginGuard(throttleRepo repository.LoginThrottleRepository, auditRepo repository.AuditLogRepository, config config.Config) LoginGuard {
+	return &loginGuard{
+		throttleRepo: throttleRepo,
+		auditRepo:    auditRepo,
+		config:       config,
+	}
+}
+
+func (g *loginGuard) WithTenant(organizationID string) LoginGuard {
+	return &loginGuard{
+		throttleRepo: g.throttleRepo.WithTenant(organizationID),
+		auditRepo:    g.auditRepo.WithTenant(organizationID),
+		config:       g.config,
+	}
+}
+
+func (g *loginGuard) Check(email, ip string) error {
+	throttles, err := g.throttleRepo.FindByKeys(emailThrottleKey(email), ipThrottleKey(ip))
+	if err != nil {
+		return err
+	}
+
+	for _, throttle := range throttles {
+		if throttle.IsLocked() {
+			return ErrTooManyAttempts
+		}
+	}
+	return nil
+}
+
+func (g *loginGuard) RecordFailure(email, ip string) error {
+	if err := g.recordFailure(emailThrottleKey(email), g.config.LoginMaxAttempts, "account", strings.ToLower(email), ip, accountLockedAction); err != nil {
+		return err
+	}
+
+	return g.recordFailure(ipThrottleKey(ip), g.config.LoginMaxAttemptsPerIP, "ip", ip, ip, ipLockedAction)
+}
+
+func (g *loginGuard) RecordSuccess(email string) error {
+	return g.throttleRepo.Reset(emailThrottleKey(email))
+}
+
+func (g *loginGuard) Unlock(email string) error {
+	return g.throttleRepo.Reset(emailThrottleKey(email))
+}
+
+// recordFailure menghitung waktu tunggu berikutnya. Sebelum batas percobaan waktu tunggu
+// berlipat dari satu detik, setelahnya akun atau IP dikunci mulai dari LoginLockoutDuration.
+func (g *loginGuard) recordFailure(key string, maxAttempts int, entityType, entityID, ip, action string) error {
+	throttle, err := g.throttleRepo.RecordFailure(key, g.config.LoginAttemptWindow)
+	if err != nil {
+		return err
+	}
+
+	var delay time.Duration
+	if throttle.Failures < maxAttempts {
+		delay = backoff(loginBackoffBase, throttle.Failures-1)
+	} else {
+		delay = backoff(g.config.LoginLockoutDuration, throttle.Failures-maxAttempts)
+	}
+
+	if err := g.throttleRepo.SetLockedUntil(key, time.Now().Add(delay)); err != nil {
+		return err
+	}
+
+	if throttle.Failures != maxAttempts {
+		return nil
+	}
+
+	details, err := json.Marshal(map[string]interface{}{
+		"failures":     throttle.Failures,
+		"locked_until": time.Now().Add(delay),
+	})
+	if err != nil {
+		return err
+	}
+
+	return g.auditRepo.Create(&entity.AuditLog{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		IP:         ip,
+		Details:    string(details),
+	})
+}
+
+func backoff(base time.Duration, exponent int) time.Duration {
+	delay := base
+	for i := 0; i < exponent && delay < maxLoginLockout; i++ {
+		delay *= 2
+	}
+
+	if delay > maxLoginLockout {
+		return maxLoginLockout
+	}
+	return delay
+}
+
+func emailThrottleKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
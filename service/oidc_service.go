@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"event-ticketing/oidc"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"sort"
+	"time"
+)
+
+const oidcStateExpiresIn = 10 * time.Minute
+
+var errUnverifiedAccountExists = errors.New("an account with this email exists but is not verified; sign in with your password and verify your email before using this provider")
+
+type OIDCService interface {
+	GetProviders() []string
+	StartLogin(provider string) (string, error)
+	CompleteLogin(provider, code, state string) (*LoginResult, error)
+	WithTenant(organizationID string) OIDCService
+	WithActor(actor Actor) OIDCService
+}
+
+type oidcService struct {
+	oidcRepo    repository.OIDCRepository
+	userRepo    repository.UserRepository
+	authService AuthService
+	providers   map[string]*oidc.Provider
+	tenantID    string
+}
+
+func NewOIDCService(oidcRepo repository.OIDCRepository, userRepo repository.UserRepository, authService AuthService, providers map[string]*oidc.Provider) OIDCService {
+	return &oidcService{
+		oidcRepo:    oidcRepo,
+		userRepo:    userRepo,
+		authService: authService,
+		providers:   providers,
+	}
+}
+
+func (s *oidcService) WithTenant(organizationID string) OIDCService {
+	return &oidcService{
+		oidcRepo:    s.oidcRepo.WithTenant(organizationID),
+		userRepo:    s.userRepo.WithTenant(organizationID),
+		authService: s.authService.WithTenant(organizationID),
+		providers:   s.providers,
+		tenantID:    organizationID,
+	}
+}
+
+// WithActor meneruskan actor ke authService yang mencatat login ke audit log
+func (s *oidcService) WithActor(actor Actor) OIDCService {
+	clone := *s
+	clone.authService = s.authService.WithActor(actor)
+	return &clone
+}
+
+func (s *oidcService) GetProviders() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartLogin menyimpan state, nonce, dan code verifier lalu mengembalikan URL
+// halaman login provider yang harus dibuka oleh frontend
+func (s *oidcService) StartLogin(provider string) (string, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", errors.New("unknown identity provider")
+	}
+
+	state, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	nonce, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	codeVerifier, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := p.AuthCodeURL(state, nonce, codeVerifier)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.oidcRepo.CreateState(&entity.OIDCState{
+		Provider:     provider,
+		StateHash:    utils.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(oidcStateExpiresIn),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// CompleteLogin memproses callback dari provider. Organisasi diambil dari state
+// yang dibuat saat StartLogin, bukan dari header request.
+func (s *oidcService) CompleteLogin(provider, code, state string) (*LoginResult, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, errors.New("unknown identity provider")
+	}
+
+	loginState, err := s.oidcRepo.ConsumeState(provider, utils.HashToken(state))
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(loginState.ExpiresAt) {
+		return nil, errors.New("invalid or expired state")
+	}
+
+	organizationID := loginState.OrganizationID.String()
+	if s.tenantID != "" && s.tenantID != organizationID {
+		return nil, errors.New("invalid or expired state")
+	}
+
+	claims, err := p.Exchange(code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant := s.WithTenant(organizationID).(*oidcService)
+	user, err := tenant.resolveUser(provider, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return tenant.authService.CompleteLogin(user)
+}
+
+// resolveUser mencari user dari identity yang sudah terhubung. Identity baru dihubungkan
+// ke user dengan email yang sama, atau user baru dibuat, hanya jika email sudah diverifikasi provider.
+// User lokal dengan email yang belum diverifikasi tidak dihubungkan.
+func (s *oidcService) resolveUser(provider string, claims *oidc.IDTokenClaims) (*entity.User, error) {
+	identity, err := s.oidcRepo.FindIdentity(provider, claims.Subject)
+	if err == nil {
+		return s.userRepo.FindByID(identity.UserID.String())
+	}
+
+	if claims.Email == "" || !bool(claims.EmailVerified) {
+		return nil, errors.New("identity provider did not return a verified email")
+	}
+
+	user, err := s.userRepo.FindByEmail(claims.Email)
+	if err != nil {
+		user, err = s.createUser(claims)
+		if err != nil {
+			return nil, err
+		}
+	} else if !user.IsEmailVerified() {
+		// Akun lokal yang emailnya belum diverifikasi bisa saja didaftarkan orang lain dengan
+		// password miliknya. Menghubungkannya akan memberi orang itu akses ke akun pemilik email.
+		return nil, errUnverifiedAccountExists
+	}
+
+	err = s.oidcRepo.CreateIdentity(&entity.UserIdentity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// createUser membuat user baru dengan password acak. User tetap bisa memasang
+// password sendiri lewat fitur lupa password.
+func (s *oidcService) createUser(claims *oidc.IDTokenClaims) (*entity.User, error) {
+	password, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = claims.Email
+	}
+
+	now := time.Now()
+	user := &entity.User{
+		Name:            name,
+		Email:           claims.Email,
+		Password:        password,
+		Role:            entity.UserRole,
+		EmailVerifiedAt: &now,
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+	"event-ticketing/config"
+	"event-ticketing/entity"
+	"event-ticketing/oidc"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+const testOIDCProvider = "mock"
+
+type oidcTestSetup struct {
+	service  OIDCService
+	oidcRepo *fakeOIDCRepository
+	userRepo *fakeUserRepository
+	client   *http.Client
+}
+
+func newOIDCTestSetup(t *testing.T) *oidcTestSetup {
+	t.Helper()
+
+	var handler http.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	mock, err := oidc.NewMockServer(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler = mock.Handler()
+
+	providers := oidc.NewProviders([]config.OIDCProviderConfig{{
+		Name:        testOIDCProvider,
+		Issuer:      server.URL,
+		ClientID:    "ticketing",
+		RedirectURL: "http://localhost:3000/auth/callback",
+		Scopes:      []string{"openid", "email", "profile"},
+	}})
+
+	setup := &oidcTestSetup{
+		oidcRepo: newFakeOIDCRepository(uuid.Must(uuid.NewV7())),
+		userRepo: newFakeUserRepository(),
+		client: &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}},
+	}
+	setup.service = NewOIDCService(setup.oidcRepo, setup.userRepo, &fakeAuthService{}, providers).
+		WithTenant(setup.oidcRepo.organizationID.String())
+	return setup
+}
+
+// authorize menjalankan StartLogin lalu membuka halaman authorize provider seperti browser,
+// dan mengembalikan code serta state dari redirect callback
+func (s *oidcTestSetup) authorize(t *testing.T, email string) (string, string) {
+	t.Helper()
+
+	authURL, err := s.service.StartLogin(testOIDCProvider)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.client.Get(authURL + "&login_hint=" + url.QueryEscape(email))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("expected redirect from authorize endpoint, got %d", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestOIDCServiceCompleteLogin(t *testing.T) {
+	setup := newOIDCTestSetup(t)
+
+	code, state := setup.authorize(t, "alice@example.com")
+	result, err := setup.service.CompleteLogin(testOIDCProvider, code, state)
+	if err != nil {
+		t.Fatalf("expected login to succeed, got %v", err)
+	}
+
+	user, err := setup.userRepo.FindByEmail("alice@example.com")
+	if err != nil {
+		t.Fatalf("expected user to be created, got %v", err)
+	}
+	if result.Token != user.ID.String() {
+		t.Fatalf("expected login for user %s, got %s", user.ID, result.Token)
+	}
+	if !user.IsEmailVerified() {
+		t.Fatal("expected user created from a verified provider email to be verified")
+	}
+
+	// Login berikutnya memakai identity yang sudah terhubung
+	code, state = setup.authorize(t, "alice@example.com")
+	result, err = setup.service.CompleteLogin(testOIDCProvider, code, state)
+	if err != nil {
+		t.Fatalf("expected second login to succeed, got %v", err)
+	}
+	if result.Token != user.ID.String() {
+		t.Fatalf("expected second login for the same user, got %s", result.Token)
+	}
+	if len(setup.oidcRepo.identities) != 1 {
+		t.Fatalf("expected one linked identity, got %d", len(setup.oidcRepo.identities))
+	}
+}
+
+func TestOIDCServiceCompleteLoginRejectsStateMismatch(t *testing.T) {
+	setup := newOIDCTestSetup(t)
+
+	code, _ := setup.authorize(t, "alice@example.com")
+	_, otherState := setup.authorize(t, "alice@example.com")
+
+	if _, err := setup.service.CompleteLogin(testOIDCProvider, code, "forged-state"); err == nil || err.Error() != "invalid or expired state" {
+		t.Fatalf("expected invalid state, got %v", err)
+	}
+
+	// State milik login lain hanya bisa dipakai sekali
+	if _, err := setup.service.CompleteLogin(testOIDCProvider, code, otherState); err == nil {
+		t.Fatal("expected code from another authorization to be rejected")
+	}
+	if _, err := setup.service.CompleteLogin(testOIDCProvider, code, otherState); err == nil || err.Error() != "invalid or expired state" {
+		t.Fatalf("expected consumed state to be rejected, got %v", err)
+	}
+}
+
+func TestOIDCServiceCompleteLoginRejectsStateFromAnotherOrganization(t *testing.T) {
+	setup := newOIDCTestSetup(t)
+
+	code, state := setup.authorize(t, "alice@example.com")
+	other := setup.service.WithTenant(uuid.Must(uuid.NewV7()).String())
+	if _, err := other.CompleteLogin(testOIDCProvider, code, state); err == nil || err.Error() != "invalid or expired state" {
+		t.Fatalf("expected invalid state, got %v", err)
+	}
+}
+
+func TestOIDCServiceCompleteLoginRejectsWrongCodeVerifier(t *testing.T) {
+	setup := newOIDCTestSetup(t)
+
+	code, state := setup.authorize(t, "alice@example.com")
+	setup.oidcRepo.only().CodeVerifier = "attacker-controlled-verifier"
+
+	_, err := setup.service.CompleteLogin(testOIDCProvider, code, state)
+	if err == nil || !strings.Contains(err.Error(), "PKCE verification failed") {
+		t.Fatalf("expected PKCE verification to fail, got %v", err)
+	}
+	if _, err := setup.userRepo.FindByEmail("alice@example.com"); err == nil {
+		t.Fatal("expected no user to be created")
+	}
+}
+
+func TestOIDCServiceCompleteLoginRejectsNonceMismatch(t *testing.T) {
+	setup := newOIDCTestSetup(t)
+
+	code, state := setup.authorize(t, "alice@example.com")
+	setup.oidcRepo.only().Nonce = "nonce-from-another-login"
+
+	_, err := setup.service.CompleteLogin(testOIDCProvider, code, state)
+	if err == nil || !strings.Contains(err.Error(), "nonce mismatch") {
+		t.Fatalf("expected nonce mismatch, got %v", err)
+	}
+	if _, err := setup.userRepo.FindByEmail("alice@example.com"); err == nil {
+		t.Fatal("expected no user to be created")
+	}
+}
+
+// Akun lokal yang belum diverifikasi bisa didaftarkan orang lain dengan email korban,
+// jadi tidak boleh dihubungkan dan ditandai terverifikasi lewat login provider
+func TestOIDCServiceCompleteLoginRefusesUnverifiedLocalAccount(t *testing.T) {
+	setup := newOIDCTestSetup(t)
+
+	attacker := &entity.User{Name: "Attacker", Email: "alice@example.com", Password: "attacker-password", Role: entity.UserRole}
+	if err := setup.userRepo.Create(attacker); err != nil {
+		t.Fatal(err)
+	}
+
+	code, state := setup.authorize(t, "alice@example.com")
+	_, err := setup.service.CompleteLogin(testOIDCProvider, code, state)
+	if !errors.Is(err, errUnverifiedAccountExists) {
+		t.Fatalf("expected %v, got %v", errUnverifiedAccountExists, err)
+	}
+
+	user, err := setup.userRepo.FindByEmail("alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.IsEmailVerified() {
+		t.Fatal("expected unverified account to stay unverified")
+	}
+	if len(setup.oidcRepo.identities) != 0 {
+		t.Fatalf("expected no linked identity, got %d", len(setup.oidcRepo.identities))
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"encoding/json"
+	"event-ticketing/config"
+	"event-ticketing/domain"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"time"
+)
+
+const (
+	// outboxBatchSize membatasi jumlah pesan yang dikirim per putaran
+	outboxBatchSize = 100
+	// outboxLease adalah waktu sebelum pesan yang sedang dikirim boleh diambil relay lain
+	outboxLease = time.Minute
+	// outboxMaxRetryDelay membatasi jeda percobaan ulang pesan yang terus gagal
+	outboxMaxRetryDelay = time.Hour
+	// outboxCleanupInterval adalah jeda antar penghapusan pesan lama yang sudah terkirim
+	outboxCleanupInterval = time.Hour
+)
+
+// recordDomainEvent menulis domain event ke outbox. Panggil dengan repository yang sudah
+// memakai transaksi perubahan datanya sehingga event ikut batal saat rollback.
+func recordDomainEvent(outboxRepo repository.OutboxRepository, event domain.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return outboxRepo.Create(&entity.OutboxMessage{
+		Type:        event.EventType(),
+		Payload:     string(payload),
+		AvailableAt: time.Now(),
+	})
+}
+
+// OutboxRelay mengirim pesan outbox ke domain.Bus minimal satu kali. Pesan yang gagal
+// dicoba lagi dengan jeda yang makin panjang sampai semua handler berhasil.
+type OutboxRelay interface {
+	RunPending() error
+	Start()
+}
+
+type outboxRelay struct {
+	outboxRepo repository.OutboxRepository
+	bus        domain.Bus
+	config     config.Config
+}
+
+func NewOutboxRelay(outboxRepo repository.OutboxRepository, bus domain.Bus, config config.Config) OutboxRelay {
+	return &outboxRelay{
+		outboxRepo: outboxRepo,
+		bus:        bus,
+		config:     config,
+	}
+}
+
+// RunPending mengirim pesan yang belum terkirim lintas organisasi
+func (r *outboxRelay) RunPending() error {
+	now := time.Now()
+
+	messages, err := r.outboxRepo.FindPending(now, outboxBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range messages {
+		message := &messages[i]
+
+		claimed, err := r.outboxRepo.Claim(message.ID, message.AvailableAt, now.Add(outboxLease))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := r.publish(message); err != nil {
+			attempts := message.Attempts + 1
+			utils.Log.Errorf("Failed to publish outbox message %s (%s, attempt %d): %v", message.ID, message.Type, attempts, err)
+			if err := r.outboxRepo.RecordFailure(message.ID, attempts, time.Now().Add(r.retryDelay(attempts)), err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := r.outboxRepo.MarkPublished(message.ID, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *outboxRelay) publish(message *entity.OutboxMessage) error {
+	event, err := domain.Decode(message.Type, []byte(message.Payload))
+	if err != nil {
+		return err
+	}
+
+	return r.bus.Publish(domain.Envelope{
+		ID:             message.ID,
+		OrganizationID: message.OrganizationID,
+		OccurredAt:     message.CreatedAt,
+		Event:          event,
+	})
+}
+
+func (r *outboxRelay) retryDelay(attempts int) time.Duration {
+	delay := r.config.OutboxRetryDelay
+	for i := 1; i < attempts && delay < outboxMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > outboxMaxRetryDelay {
+		return outboxMaxRetryDelay
+	}
+	return delay
+}
+
+// Start menjalankan relay di background dan menghapus pesan terkirim yang lebih lama dari OutboxRetention
+func (r *outboxRelay) Start() {
+	if r.config.OutboxRelayInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(r.config.OutboxRelayInterval)
+		defer ticker.Stop()
+		cleanup := time.NewTicker(outboxCleanupInterval)
+		defer cleanup.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.RunPending(); err != nil {
+					utils.Log.Errorf("Failed to relay outbox messages: %v", err)
+				}
+			case <-cleanup.C:
+				deleted, err := r.outboxRepo.DeletePublishedBefore(time.Now().Add(-r.config.OutboxRetention))
+				if err != nil {
+					utils.Log.Errorf("Failed to clean up outbox: %v", err)
+				} else if deleted > 0 {
+					utils.Log.Infof("Deleted %d published outbox messages", deleted)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"event-ticketing/config"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// QueueAdmitter mengizinkan user di ruang tunggu masuk secara berkala, lintas organisasi.
+// Dibuat terpisah dari QueueService agar salinan per tenant tidak ikut membawa state worker.
+type QueueAdmitter interface {
+	Admit()
+	Start()
+}
+
+type queueAdmitter struct {
+	queueRepo repository.QueueRepository
+	config    config.Config
+
+	mu    sync.Mutex
+	carry map[uuid.UUID]float64
+}
+
+func NewQueueAdmitter(queueRepo repository.QueueRepository, config config.Config) QueueAdmitter {
+	return &queueAdmitter{
+		queueRepo: queueRepo,
+		config:    config,
+		carry:     map[uuid.UUID]float64{},
+	}
+}
+
+// Start menjalankan worker yang mengizinkan user masuk secara berkala. Setiap instance
+// menjalankan worker sendiri, jadi rate efektif adalah QueueAdmitRate dikali jumlah instance.
+func (a *queueAdmitter) Start() {
+	if a.config.QueueAdmitInterval <= 0 {
+		utils.Log.Warn("Queue admit interval is not set, waiting rooms will not admit anyone")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(a.config.QueueAdmitInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			a.Admit()
+		}
+	}()
+}
+
+func (a *queueAdmitter) Admit() {
+	if _, err := a.queueRepo.ExpireAdmissions(); err != nil {
+		utils.Log.Errorf("Failed to expire queue admissions: %v", err)
+	}
+
+	events, err := a.queueRepo.FindWaitingEvents()
+	if err != nil {
+		utils.Log.Errorf("Failed to load queued events: %v", err)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	active := map[uuid.UUID]float64{}
+	for _, event := range events {
+		// Sisa pecahan dibawa ke putaran berikutnya agar rate per menit tetap akurat
+		quota := a.carry[event.ID] + float64(event.QueueAdmitRate)*a.config.QueueAdmitInterval.Minutes()
+		limit := int(quota)
+		active[event.ID] = quota - float64(limit)
+		if limit == 0 {
+			continue
+		}
+
+		expiresAt := time.Now().Add(a.config.QueueAdmissionWindow)
+		if _, err := a.queueRepo.AdmitNext(event.ID.String(), limit, expiresAt); err != nil {
+			utils.Log.Errorf("Failed to admit queue for event %s: %v", event.ID, err)
+		}
+	}
+	a.carry = active
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var ErrQueueAdmissionRequired = errors.New("a valid queue admission token is required for this event")
+
+// QueueStatus adalah posisi user di ruang tunggu. Token hanya dikirim saat bergabung.
+type QueueStatus struct {
+	Token      string                  `json:"token,omitempty"`
+	EventID    uuid.UUID               `json:"event_id"`
+	Status     entity.QueueEntryStatus `json:"status"`
+	Position   int64                   `json:"position"`
+	AdmittedAt *time.Time              `json:"admitted_at"`
+	ExpiresAt  *time.Time              `json:"expires_at"`
+}
+
+// QueueService mengatur ruang tunggu untuk event dengan permintaan tinggi.
+// User bergabung ke antrean, lalu QueueAdmitter di background mengizinkan masuk
+// sejumlah user per menit sesuai QueueAdmitRate event.
+type QueueService interface {
+	JoinQueue(eventID, userID string) (*QueueStatus, error)
+	GetStatus(eventID, token string) (*QueueStatus, error)
+	WithTenant(organizationID string) QueueService
+}
+
+type queueService struct {
+	queueRepo repository.QueueRepository
+	eventRepo repository.EventRepository
+}
+
+func NewQueueService(queueRepo repository.QueueRepository, eventRepo repository.EventRepository) QueueService {
+	return &queueService{
+		queueRepo: queueRepo,
+		eventRepo: eventRepo,
+	}
+}
+
+func (s *queueService) WithTenant(organizationID string) QueueService {
+	return &queueService{
+		queueRepo: s.queueRepo.WithTenant(organizationID),
+		eventRepo: s.eventRepo.WithTenant(organizationID),
+	}
+}
+
+// JoinQueue memasukkan user ke antrean. User yang sudah mengantre mendapat token
+// baru tanpa kehilangan posisinya.
+func (s *queueService) JoinQueue(eventID, userID string) (*QueueStatus, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !event.QueueEnabled {
+		return nil, errors.New("event does not use a waiting room")
+	}
+
+	if event.Status != entity.ActiveEvent {
+		return nil, errors.New("event not active")
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.queueRepo.FindActiveByUser(eventID, userID)
+	if err == nil && (entry.Status == entity.QueueWaiting || entry.IsAdmitted()) {
+		if err := s.queueRepo.UpdateTokenHash(entry.ID.String(), utils.HashToken(token)); err != nil {
+			return nil, err
+		}
+	} else {
+		userUUID, err := uuid.FromString(userID)
+		if err != nil {
+			return nil, errors.New("invalid user ID")
+		}
+
+		entry = &entity.QueueEntry{
+			EventID:   event.ID,
+			UserID:    userUUID,
+			TokenHash: utils.HashToken(token),
+			Status:    entity.QueueWaiting,
+		}
+		if err := s.queueRepo.Create(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	status, err := s.buildStatus(entry)
+	if err != nil {
+		return nil, err
+	}
+	status.Token = token
+
+	return status, nil
+}
+
+func (s *queueService) GetStatus(eventID, token string) (*QueueStatus, error) {
+	if token == "" {
+		return nil, errors.New("queue token is required")
+	}
+
+	entry, err := s.queueRepo.FindByHash(eventID, utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildStatus(entry)
+}
+
+func (s *queueService) buildStatus(entry *entity.QueueEntry) (*QueueStatus, error) {
+	status := &QueueStatus{
+		EventID:    entry.EventID,
+		Status:     entry.Status,
+		AdmittedAt: entry.AdmittedAt,
+		ExpiresAt:  entry.ExpiresAt,
+	}
+
+	// Status expired baru ditulis worker pada putaran berikutnya
+	if entry.Status == entity.QueueAdmitted && !entry.IsAdmitted() {
+		status.Status = entity.QueueExpired
+	}
+
+	if entry.Status == entity.QueueWaiting {
+		ahead, err := s.queueRepo.CountAhead(entry)
+		if err != nil {
+			return nil, err
+		}
+		status.Position = ahead + 1
+	}
+
+	return status, nil
+}
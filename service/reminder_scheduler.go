@@ -0,0 +1,99 @@
+package service
+
+import (
+	"event-ticketing/config"
+	"event-ticketing/notification"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// reminderBatchSize membatasi jumlah tiket per offset yang diproses per putaran
+const reminderBatchSize = 200
+
+// ReminderScheduler mengirim pengingat event ke pemegang tiket pada setiap offset
+// sebelum event dimulai, misalnya 7 hari, 1 hari, dan 2 jam sebelumnya
+type ReminderScheduler interface {
+	RunDue() error
+	Start()
+}
+
+type reminderScheduler struct {
+	db           *gorm.DB
+	reminderRepo repository.TicketReminderRepository
+	notifier     notification.Notifier
+	offsets      []time.Duration
+	config       config.Config
+}
+
+func NewReminderScheduler(db *gorm.DB, reminderRepo repository.TicketReminderRepository, notifier notification.Notifier, config config.Config) ReminderScheduler {
+	offsets := append([]time.Duration(nil), config.ReminderOffsets...)
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] > offsets[j] })
+
+	return &reminderScheduler{
+		db:           db,
+		reminderRepo: reminderRepo,
+		notifier:     notifier,
+		offsets:      offsets,
+		config:       config,
+	}
+}
+
+// RunDue mengirim pengingat yang jatuh tempo lintas organisasi. Setiap offset hanya
+// mencakup waktu sampai offset berikutnya, jadi pengingat yang terlewat saat aplikasi
+// mati tidak dikirim bersamaan dengan pengingat yang lebih dekat. Pengingat di-claim
+// dalam transaksi yang sama dengan notifikasinya, sehingga setiap tiket menerima tiap
+// offset tepat sekali.
+func (s *reminderScheduler) RunDue() error {
+	now := time.Now()
+
+	for i, offset := range s.offsets {
+		var nextOffset time.Duration
+		if i+1 < len(s.offsets) {
+			nextOffset = s.offsets[i+1]
+		}
+
+		tickets, err := s.reminderRepo.FindDue(offset, nextOffset, now, reminderBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for j := range tickets {
+			ticket := &tickets[j]
+
+			err := s.db.Transaction(func(tx *gorm.DB) error {
+				claimed, err := s.reminderRepo.WithTx(tx).Claim(ticket, offset)
+				if err != nil || !claimed {
+					return err
+				}
+				return s.notifier.WithTx(tx).EventReminder(ticket)
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Start menjalankan pengiriman pengingat berkala di background
+func (s *reminderScheduler) Start() {
+	if s.config.ReminderSchedulerInterval <= 0 || len(s.offsets) == 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.config.ReminderSchedulerInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := s.RunDue(); err != nil {
+				utils.Log.Errorf("Failed to send event reminders: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"event-ticketing/config"
+	"event-ticketing/entity"
+	"event-ticketing/mailer"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"fmt"
+	"html"
+	"time"
+)
+
+// reportSchedulerBatchSize membatasi jumlah langganan yang diproses per putaran
+const reportSchedulerBatchSize = 50
+
+// ReportScheduler mengirim laporan langganan yang sudah jatuh tempo. CSV dikirim sebagai
+// lampiran, HTML langsung sebagai isi email.
+type ReportScheduler interface {
+	RunDue() error
+	Start()
+}
+
+type reportScheduler struct {
+	subscriptionRepo repository.ReportSubscriptionRepository
+	reportService    ReportService
+	mailer           mailer.Mailer
+	config           config.Config
+}
+
+func NewReportScheduler(subscriptionRepo repository.ReportSubscriptionRepository, reportService ReportService, mailer mailer.Mailer, config config.Config) ReportScheduler {
+	return &reportScheduler{
+		subscriptionRepo: subscriptionRepo,
+		reportService:    reportService,
+		mailer:           mailer,
+		config:           config,
+	}
+}
+
+// RunDue mengirim semua langganan yang jatuh tempo lintas organisasi. Jadwal berikutnya
+// di-claim sebelum laporan dibuat, jadi pengiriman yang gagal tidak diulang dan
+// kesalahannya disimpan di last_error.
+func (s *reportScheduler) RunDue() error {
+	now := time.Now()
+
+	subscriptions, err := s.subscriptionRepo.FindDue(now, reportSchedulerBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+
+		claimed, err := s.subscriptionRepo.Claim(subscription.ID, subscription.NextRunAt, nextReportRun(subscription, now))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		var lastError string
+		if err := s.send(subscription); err != nil {
+			utils.Log.Errorf("Failed to send report subscription %s: %v", subscription.ID, err)
+			lastError = err.Error()
+		}
+
+		if err := s.subscriptionRepo.RecordRun(subscription.ID, now, lastError); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextReportRun menghitung jadwal berikutnya setelah now sehingga jadwal yang terlewat
+// saat aplikasi mati tidak dikirim berulang kali
+func nextReportRun(subscription *entity.ReportSubscription, now time.Time) time.Time {
+	schedule, err := utils.ParseCron(subscription.Schedule)
+	if err != nil {
+		return now.Add(24 * time.Hour)
+	}
+
+	loc, err := time.LoadLocation(subscription.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	next := schedule.Next(now, loc)
+	if next.IsZero() {
+		return now.Add(24 * time.Hour)
+	}
+	return next
+}
+
+func (s *reportScheduler) send(subscription *entity.ReportSubscription) error {
+	reportService := s.reportService.WithTenant(subscription.OrganizationID.String())
+
+	var writeTable func(w utils.TableWriter) error
+	switch subscription.ReportType {
+	case entity.SummaryReportType:
+		report, err := reportService.GenerateSummaryReport()
+		if err != nil {
+			return err
+		}
+		writeTable = report.WriteTable
+	case entity.EventReportType:
+		report, err := reportService.GenerateEventReport(subscription.EventID.String())
+		if err != nil {
+			return err
+		}
+		writeTable = report.WriteTable
+	case entity.SalesReportType:
+		report, err := reportService.GenerateSalesReport(subscription.EventID.String(), DailySales, subscription.Timezone)
+		if err != nil {
+			return err
+		}
+		writeTable = report.WriteTable
+	default:
+		return errors.New("unknown report type")
+	}
+
+	var table bytes.Buffer
+	w, err := utils.NewTableWriter(&table, string(subscription.Format), string(subscription.ReportType))
+	if err != nil {
+		return err
+	}
+	if err := writeTable(w); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	generatedAt := time.Now()
+	message := mailer.Message{
+		To:      subscription.RecipientList(),
+		Subject: fmt.Sprintf("%s - %s report", subscription.Name, subscription.ReportType),
+		Body:    fmt.Sprintf("Your %s report \"%s\" generated at %s.", subscription.ReportType, subscription.Name, generatedAt.Format(time.RFC1123)),
+	}
+
+	if subscription.Format == entity.HTMLReportFormat {
+		message.HTMLBody = fmt.Sprintf("<p>%s</p>%s", html.EscapeString(message.Body), table.String())
+	} else {
+		message.Body += " The report is attached."
+		message.Attachments = []mailer.Attachment{{
+			Filename:    fmt.Sprintf("%s-report-%s.csv", subscription.ReportType, generatedAt.Format("20060102150405")),
+			ContentType: utils.TableContentType(utils.CSVFormat),
+			Data:        table.Bytes(),
+		}}
+	}
+
+	return s.mailer.Send(message)
+}
+
+// Start menjalankan pengiriman laporan berkala di background
+func (s *reportScheduler) Start() {
+	if s.config.ReportSchedulerInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.config.ReportSchedulerInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := s.RunDue(); err != nil {
+				utils.Log.Errorf("Failed to run report subscriptions: %v", err)
+			}
+		}
+	}()
+}
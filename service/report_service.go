@@ -1,34 +1,176 @@
 package service
 
 import (
+	"errors"
 	"event-ticketing/entity"
 	"event-ticketing/repository"
 	"event-ticketing/utils"
 	"math"
 	"time"
+
+	"github.com/gofrs/uuid/v5"
 )
 
+// arrivalBucketSize adalah lebar bucket histogram waktu kedatangan
+const arrivalBucketSize = 15 * time.Minute
+
 type SummaryReport struct {
 	TotalEvents    int       `json:"total_events"`
 	TotalTickets   int       `json:"total_tickets"`
 	TotalSold      int       `json:"total_sold"`
 	TotalCancelled int       `json:"total_cancelled"`
 	TotalRevenue   float64   `json:"total_revenue"`
+	TotalCheckedIn int       `json:"total_checked_in"`
+	TotalNoShows   int       `json:"total_no_shows"`
+	CheckInRate    float64   `json:"check_in_rate"`
 	GeneratedAt    time.Time `json:"generated_at"`
 }
 
 type EventReport struct {
-	Event            entity.Event `json:"event"`
-	TotalTickets     int          `json:"total_tickets"`
-	SoldTickets      int          `json:"sold_tickets"`
-	CancelledTickets int          `json:"cancelled_tickets"`
-	Revenue          float64      `json:"revenue"`
-	GeneratedAt      time.Time    `json:"generated_at"`
+	Event            entity.Event     `json:"event"`
+	TotalTickets     int              `json:"total_tickets"`
+	SoldTickets      int              `json:"sold_tickets"`
+	CancelledTickets int              `json:"cancelled_tickets"`
+	Revenue          float64          `json:"revenue"`
+	Attendance       AttendanceReport `json:"attendance"`
+	GeneratedAt      time.Time        `json:"generated_at"`
+}
+
+// EventReportTotals adalah angka utama laporan event yang dikirim lewat stream live.
+// Isinya total terkini, bukan selisih, sehingga pesan yang terkirim ulang atau datang
+// setelah laporan awal tidak membuat angka dihitung dua kali. MessageID adalah ID pesan
+// outbox yang memicu perubahan.
+type EventReportTotals struct {
+	MessageID        uuid.UUID `json:"message_id"`
+	TotalTickets     int       `json:"total_tickets"`
+	SoldTickets      int       `json:"sold_tickets"`
+	CancelledTickets int       `json:"cancelled_tickets"`
+	Revenue          float64   `json:"revenue"`
+	CheckedIn        int       `json:"checked_in"`
+	GeneratedAt      time.Time `json:"generated_at"`
+}
+
+// AttendanceReport berisi kehadiran sebuah event. CheckInRate adalah check-in dibagi
+// tiket terjual. No-show adalah tiket terjual yang belum check-in setelah event dimulai.
+type AttendanceReport struct {
+	CheckedIn   int                    `json:"checked_in"`
+	NoShows     int                    `json:"no_shows"`
+	CheckInRate float64                `json:"check_in_rate"`
+	Arrivals    []ArrivalBucket        `json:"arrivals"`
+	Gates       []repository.GateCount `json:"gates"`
+	NoShowList  []NoShow               `json:"no_show_list"`
+}
+
+// ArrivalBucket adalah jumlah check-in dalam satu slot 15 menit
+type ArrivalBucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+type NoShow struct {
+	TicketID    string `json:"ticket_id"`
+	BookingCode string `json:"booking_code"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+}
+
+// SalesInterval adalah ukuran bucket pada laporan penjualan
+type SalesInterval string
+
+const (
+	HourlySales SalesInterval = "hour"
+	DailySales  SalesInterval = "day"
+	WeeklySales SalesInterval = "week"
+)
+
+// SalesBucket berisi penjualan dalam satu bucket. Revenue adalah pendapatan bersih,
+// yaitu harga tiket yang terjual dikurangi harga tiket yang dibatalkan pada bucket itu.
+type SalesBucket struct {
+	Start             time.Time `json:"start"`
+	Sold              int       `json:"sold"`
+	Cancelled         int       `json:"cancelled"`
+	Revenue           float64   `json:"revenue"`
+	CumulativeSold    int       `json:"cumulative_sold"`
+	CumulativeRevenue float64   `json:"cumulative_revenue"`
+}
+
+type SalesReport struct {
+	EventID     string        `json:"event_id"`
+	Interval    SalesInterval `json:"interval"`
+	Timezone    string        `json:"timezone"`
+	Buckets     []SalesBucket `json:"buckets"`
+	GeneratedAt time.Time     `json:"generated_at"`
+}
+
+// WriteTable menulis ringkasan sebagai tabel metrik dan nilai
+func (r *SummaryReport) WriteTable(w utils.TableWriter) error {
+	rows := [][]interface{}{
+		{"metric", "value"},
+		{"total_events", r.TotalEvents},
+		{"total_tickets", r.TotalTickets},
+		{"total_sold", r.TotalSold},
+		{"total_cancelled", r.TotalCancelled},
+		{"total_revenue", r.TotalRevenue},
+		{"total_checked_in", r.TotalCheckedIn},
+		{"total_no_shows", r.TotalNoShows},
+		{"check_in_rate", r.CheckInRate},
+		{"generated_at", r.GeneratedAt},
+	}
+	return writeRows(w, rows)
+}
+
+// WriteTable menulis laporan event sebagai tabel metrik dan nilai
+func (r *EventReport) WriteTable(w utils.TableWriter) error {
+	rows := [][]interface{}{
+		{"metric", "value"},
+		{"event_id", r.Event.ID},
+		{"event_name", r.Event.Name},
+		{"start_date", r.Event.StartDate},
+		{"end_date", r.Event.EndDate},
+		{"location", r.Event.Location},
+		{"status", r.Event.Status},
+		{"total_tickets", r.TotalTickets},
+		{"sold_tickets", r.SoldTickets},
+		{"cancelled_tickets", r.CancelledTickets},
+		{"revenue", r.Revenue},
+		{"checked_in", r.Attendance.CheckedIn},
+		{"no_shows", r.Attendance.NoShows},
+		{"check_in_rate", r.Attendance.CheckInRate},
+		{"generated_at", r.GeneratedAt},
+	}
+	return writeRows(w, rows)
+}
+
+// WriteTable menulis laporan penjualan dengan satu baris per bucket
+func (r *SalesReport) WriteTable(w utils.TableWriter) error {
+	if err := w.WriteRow("start", "sold", "cancelled", "revenue", "cumulative_sold", "cumulative_revenue"); err != nil {
+		return err
+	}
+
+	for _, bucket := range r.Buckets {
+		err := w.WriteRow(bucket.Start, bucket.Sold, bucket.Cancelled, bucket.Revenue, bucket.CumulativeSold, bucket.CumulativeRevenue)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeRows(w utils.TableWriter, rows [][]interface{}) error {
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type ReportService interface {
 	GenerateSummaryReport() (*SummaryReport, error)
 	GenerateEventReport(eventID string) (*EventReport, error)
+	GenerateEventTotals(eventID string) (*EventReportTotals, error)
+	GenerateSalesReport(eventID string, interval SalesInterval, timezone string) (*SalesReport, error)
+	WithTenant(organizationID string) ReportService
 }
 
 type reportService struct {
@@ -49,40 +191,41 @@ func NewReportService(
 	}
 }
 
+func (s *reportService) WithTenant(organizationID string) ReportService {
+	return &reportService{
+		eventRepo:  s.eventRepo.WithTenant(organizationID),
+		userRepo:   s.userRepo.WithTenant(organizationID),
+		ticketRepo: s.ticketRepo.WithTenant(organizationID),
+	}
+}
+
 func (s *reportService) GenerateSummaryReport() (*SummaryReport, error) {
-	var totalTickets int = 0
-	var soldTickets int = 0
-	var cancelledTickets int = 0
-	var totalRevenue float64 = 0
+	totalEvents, totalTickets, err := s.eventRepo.GetCapacitySummary()
+	if err != nil {
+		return nil, err
+	}
 
-	events, totalEvents, err := s.eventRepo.FindAll(utils.PaginationParams{Page: 1, Limit: math.MaxInt64}, "", "", "", "")
+	stats, err := s.ticketRepo.GetStatsByEvent()
 	if err != nil {
 		return nil, err
 	}
 
-	for _, event := range events {
-		totalTickets += event.Capacity
-		soldTickets += event.TicketsSold
+	report := &SummaryReport{
+		TotalEvents:  int(totalEvents),
+		TotalTickets: totalTickets,
+		GeneratedAt:  time.Now(),
+	}
 
-		cancelledTickets, err = s.ticketRepo.CountByEventAndStatus(event.ID.String(), entity.CancelledTicket)
-		if err != nil {
-			return nil, err
-		}
-		revenue, err := s.ticketRepo.GetRevenue(event.ID.String())
-		if err != nil {
-			return nil, err
-		}
-		totalRevenue += revenue
+	for _, stat := range stats {
+		report.TotalSold += stat.Sold
+		report.TotalCancelled += stat.Cancelled
+		report.TotalRevenue += stat.Revenue
+		report.TotalCheckedIn += stat.CheckedIn
+		report.TotalNoShows += stat.NoShows
 	}
+	report.CheckInRate = checkInRate(report.TotalCheckedIn, report.TotalSold)
 
-	return &SummaryReport{
-		TotalEvents:    int(totalEvents),
-		TotalTickets:   totalTickets,
-		TotalSold:      soldTickets,
-		TotalCancelled: cancelledTickets,
-		TotalRevenue:   totalRevenue,
-		GeneratedAt:    time.Now(),
-	}, nil
+	return report, nil
 }
 
 func (s *reportService) GenerateEventReport(eventID string) (*EventReport, error) {
@@ -91,27 +234,223 @@ func (s *reportService) GenerateEventReport(eventID string) (*EventReport, error
 		return nil, err
 	}
 
-	soldTickets, err := s.ticketRepo.CountByEventAndStatus(eventID, entity.PurchasedTicket)
+	stats, err := s.ticketRepo.GetStatsByEvent(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &EventReport{
+		Event:        *event,
+		TotalTickets: event.Capacity,
+		GeneratedAt:  time.Now(),
+	}
+
+	if len(stats) > 0 {
+		report.SoldTickets = stats[0].Sold
+		report.CancelledTickets = stats[0].Cancelled
+		report.Revenue = stats[0].Revenue
+		report.Attendance.CheckedIn = stats[0].CheckedIn
+		report.Attendance.NoShows = stats[0].NoShows
+	}
+
+	if err := s.fillAttendance(event, &report.Attendance, report.SoldTickets); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// GenerateEventTotals menghitung angka utama laporan event tanpa daftar kehadiran,
+// cukup ringan untuk dijalankan setiap kali tiket event berubah
+func (s *reportService) GenerateEventTotals(eventID string) (*EventReportTotals, error) {
+	event, err := s.eventRepo.FindByID(eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := s.ticketRepo.GetStatsByEvent(eventID)
 	if err != nil {
 		return nil, err
 	}
 
-	cancelledTickets, err := s.ticketRepo.CountByEventAndStatus(eventID, entity.CancelledTicket)
+	totals := &EventReportTotals{
+		TotalTickets: event.Capacity,
+		GeneratedAt:  time.Now(),
+	}
+	if len(stats) > 0 {
+		totals.SoldTickets = stats[0].Sold
+		totals.CancelledTickets = stats[0].Cancelled
+		totals.Revenue = stats[0].Revenue
+		totals.CheckedIn = stats[0].CheckedIn
+	}
+
+	return totals, nil
+}
+
+func (s *reportService) fillAttendance(event *entity.Event, attendance *AttendanceReport, sold int) error {
+	attendance.CheckInRate = checkInRate(attendance.CheckedIn, sold)
+	attendance.Arrivals = []ArrivalBucket{}
+	attendance.NoShowList = []NoShow{}
+
+	checkIns, err := s.ticketRepo.FindCheckInTimesByEventID(event.ID.String())
+	if err != nil {
+		return err
+	}
+
+	// Bucket kosong di antara check-in pertama dan terakhir tetap diisi agar histogram rapat
+	if len(checkIns) > 0 {
+		start := checkIns[0].Truncate(arrivalBucketSize)
+		for _, checkIn := range checkIns {
+			bucketStart := checkIn.Truncate(arrivalBucketSize)
+			for len(attendance.Arrivals) == 0 || attendance.Arrivals[len(attendance.Arrivals)-1].Start.Before(bucketStart) {
+				attendance.Arrivals = append(attendance.Arrivals, ArrivalBucket{Start: start.Add(time.Duration(len(attendance.Arrivals)) * arrivalBucketSize)})
+			}
+			attendance.Arrivals[len(attendance.Arrivals)-1].Count++
+		}
+	}
+
+	attendance.Gates, err = s.ticketRepo.CountCheckInsByGate(event.ID.String())
+	if err != nil {
+		return err
+	}
+	if attendance.Gates == nil {
+		attendance.Gates = []repository.GateCount{}
+	}
+
+	if time.Now().Before(event.StartDate) {
+		return nil
+	}
+
+	noShows, err := s.ticketRepo.FindNoShowsByEventID(event.ID.String())
 	if err != nil {
+		return err
+	}
+
+	for _, noShow := range noShows {
+		attendance.NoShowList = append(attendance.NoShowList, NoShow{
+			TicketID:    noShow.TicketID.String(),
+			BookingCode: noShow.BookingCode,
+			Name:        noShow.UserName,
+			Email:       noShow.UserEmail,
+		})
+	}
+
+	return nil
+}
+
+func checkInRate(checkedIn, sold int) float64 {
+	if sold == 0 {
+		return 0
+	}
+	return math.Round(float64(checkedIn)/float64(sold)*10000) / 10000
+}
+
+// GenerateSalesReport mengelompokkan penjualan dan pembatalan sebuah event per jam, hari,
+// atau minggu (mulai Senin) menurut zona waktu yang diminta. Bucket kosong tetap diisi.
+func (s *reportService) GenerateSalesReport(eventID string, interval SalesInterval, timezone string) (*SalesReport, error) {
+	if interval != HourlySales && interval != DailySales && interval != WeeklySales {
+		return nil, errors.New("interval must be one of hour, day, week")
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, errors.New("invalid timezone")
+	}
+
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
 		return nil, err
 	}
 
-	revenue, err := s.ticketRepo.GetRevenue(eventID)
+	sales, err := s.ticketRepo.FindSalesByEventID(eventID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &EventReport{
-		Event:            *event,
-		TotalTickets:     event.Capacity,
-		SoldTickets:      soldTickets,
-		CancelledTickets: cancelledTickets,
-		Revenue:          revenue,
-		GeneratedAt:      time.Now(),
-	}, nil
+	report := &SalesReport{
+		EventID:     eventID,
+		Interval:    interval,
+		Timezone:    loc.String(),
+		Buckets:     []SalesBucket{},
+		GeneratedAt: time.Now(),
+	}
+
+	if len(sales) == 0 {
+		return report, nil
+	}
+
+	buckets := map[int64]*SalesBucket{}
+	bucketAt := func(t time.Time) *SalesBucket {
+		start := salesBucketStart(t, interval, loc)
+		bucket, ok := buckets[start.Unix()]
+		if !ok {
+			bucket = &SalesBucket{Start: start}
+			buckets[start.Unix()] = bucket
+		}
+		return bucket
+	}
+
+	first, last := sales[0].PurchaseDate, sales[0].PurchaseDate
+	for _, sale := range sales {
+		bucket := bucketAt(sale.PurchaseDate)
+		bucket.Sold++
+		bucket.Revenue += sale.Price
+		if sale.PurchaseDate.After(last) {
+			last = sale.PurchaseDate
+		}
+
+		if sale.CancelledAt != nil {
+			bucket := bucketAt(*sale.CancelledAt)
+			bucket.Cancelled++
+			bucket.Revenue -= sale.Price
+			if sale.CancelledAt.After(last) {
+				last = *sale.CancelledAt
+			}
+		}
+	}
+
+	var cumulativeSold int
+	var cumulativeRevenue float64
+	end := salesBucketStart(last, interval, loc)
+	for start := salesBucketStart(first, interval, loc); !start.After(end); start = nextSalesBucket(start, interval) {
+		bucket := SalesBucket{Start: start}
+		if b, ok := buckets[start.Unix()]; ok {
+			bucket = *b
+		}
+
+		cumulativeSold += bucket.Sold - bucket.Cancelled
+		cumulativeRevenue += bucket.Revenue
+		bucket.CumulativeSold = cumulativeSold
+		bucket.CumulativeRevenue = cumulativeRevenue
+		report.Buckets = append(report.Buckets, bucket)
+	}
+
+	return report, nil
+}
+
+func salesBucketStart(t time.Time, interval SalesInterval, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch interval {
+	case HourlySales:
+		// Dipotong dari menit lokal, bukan time.Date, agar jam yang berulang saat DST tidak tertukar
+		return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case WeeklySales:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+}
+
+// nextSalesBucket memakai AddDate untuk hari dan minggu agar bucket tetap dimulai
+// tengah malam saat terjadi pergantian daylight saving time. Zona waktu diambil dari start,
+// yang sudah berada di zona laporan karena dibuat oleh salesBucketStart.
+func nextSalesBucket(start time.Time, interval SalesInterval) time.Time {
+	switch interval {
+	case HourlySales:
+		return start.Add(time.Hour)
+	case WeeklySales:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
 }
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+type ReportSubscriptionService interface {
+	CreateSubscription(subscription *entity.ReportSubscription) error
+	GetAllSubscriptions(params utils.PaginationParams) ([]entity.ReportSubscription, int64, error)
+	DeleteSubscription(id string) error
+	WithTenant(organizationID string) ReportSubscriptionService
+	WithActor(actor Actor) ReportSubscriptionService
+}
+
+type reportSubscriptionService struct {
+	subscriptionRepo repository.ReportSubscriptionRepository
+	eventRepo        repository.EventRepository
+	auditService     AuditService
+	actor            Actor
+}
+
+func NewReportSubscriptionService(subscriptionRepo repository.ReportSubscriptionRepository, eventRepo repository.EventRepository, auditService AuditService) ReportSubscriptionService {
+	return &reportSubscriptionService{
+		subscriptionRepo: subscriptionRepo,
+		eventRepo:        eventRepo,
+		auditService:     auditService,
+	}
+}
+
+func (s *reportSubscriptionService) WithTenant(organizationID string) ReportSubscriptionService {
+	return &reportSubscriptionService{
+		subscriptionRepo: s.subscriptionRepo.WithTenant(organizationID),
+		eventRepo:        s.eventRepo.WithTenant(organizationID),
+		auditService:     s.auditService.WithTenant(organizationID),
+		actor:            s.actor,
+	}
+}
+
+// WithActor mengembalikan salinan service yang mencatat perubahan atas nama actor
+func (s *reportSubscriptionService) WithActor(actor Actor) ReportSubscriptionService {
+	clone := *s
+	clone.actor = actor
+	return &clone
+}
+
+// CreateSubscription memvalidasi jadwal, zona waktu, dan event lalu menghitung
+// waktu pengiriman pertama
+func (s *reportSubscriptionService) CreateSubscription(subscription *entity.ReportSubscription) error {
+	schedule, err := utils.ParseCron(subscription.Schedule)
+	if err != nil {
+		return err
+	}
+
+	loc, err := time.LoadLocation(subscription.Timezone)
+	if err != nil {
+		return errors.New("invalid timezone")
+	}
+
+	switch subscription.ReportType {
+	case entity.SummaryReportType:
+		if subscription.EventID != nil {
+			return errors.New("summary report does not take an event")
+		}
+	case entity.EventReportType, entity.SalesReportType:
+		if subscription.EventID == nil {
+			return errors.New("event is required for this report type")
+		}
+		if _, err := s.eventRepo.FindByID(subscription.EventID.String()); err != nil {
+			return err
+		}
+	default:
+		return errors.New("report type must be one of summary, event, sales")
+	}
+
+	if subscription.Format != entity.CSVReportFormat && subscription.Format != entity.HTMLReportFormat {
+		return errors.New("format must be csv or html")
+	}
+
+	if len(subscription.RecipientList()) == 0 {
+		return errors.New("at least one recipient is required")
+	}
+
+	subscription.NextRunAt = schedule.Next(time.Now(), loc)
+	if subscription.NextRunAt.IsZero() {
+		return errors.New("schedule never runs")
+	}
+	subscription.CreatedByID = uuid.FromStringOrNil(s.actor.UserID)
+
+	if err := s.subscriptionRepo.Create(subscription); err != nil {
+		return err
+	}
+
+	s.auditService.Record(s.actor, "report_subscription.created", "report_subscription", subscription.ID.String(), nil, subscription)
+	return nil
+}
+
+func (s *reportSubscriptionService) GetAllSubscriptions(params utils.PaginationParams) ([]entity.ReportSubscription, int64, error) {
+	return s.subscriptionRepo.FindAll(params)
+}
+
+func (s *reportSubscriptionService) DeleteSubscription(id string) error {
+	subscription, err := s.subscriptionRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.subscriptionRepo.Delete(id); err != nil {
+		return err
+	}
+
+	s.auditService.Record(s.actor, "report_subscription.deleted", "report_subscription", id, subscription, nil)
+	return nil
+}
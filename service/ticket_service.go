@@ -2,6 +2,7 @@ package service
 
 import (
 	"errors"
+	"event-ticketing/domain"
 	"event-ticketing/entity"
 	"event-ticketing/repository"
 	"event-ticketing/utils"
@@ -18,27 +19,65 @@ type TicketService interface {
 	GetTicketsByUserID(userID string, params utils.PaginationParams) ([]entity.Ticket, int64, error)
 	GetTicketsByEventID(eventID string, params utils.PaginationParams) ([]entity.Ticket, int64, error)
 	CancelTicket(id string) error
+	ExportEventTickets(eventID string, w utils.TableWriter) error
+	CheckIn(bookingCode, gate string) (*entity.Ticket, error)
+	WithTenant(organizationID string) TicketService
+	WithActor(actor Actor) TicketService
 }
 
 type ticketService struct {
-	db         *gorm.DB
-	ticketRepo repository.TicketRepository
-	eventRepo  repository.EventRepository
+	db           *gorm.DB
+	ticketRepo   repository.TicketRepository
+	eventRepo    repository.EventRepository
+	queueRepo    repository.QueueRepository
+	outboxRepo   repository.OutboxRepository
+	auditService AuditService
+	actor        Actor
 }
 
 func NewTicketService(
 	db *gorm.DB,
 	ticketRepo repository.TicketRepository,
 	eventRepo repository.EventRepository,
+	queueRepo repository.QueueRepository,
+	outboxRepo repository.OutboxRepository,
+	auditService AuditService,
 ) TicketService {
 	return &ticketService{
-		db:         db,
-		ticketRepo: ticketRepo,
-		eventRepo:  eventRepo,
+		db:           db,
+		ticketRepo:   ticketRepo,
+		eventRepo:    eventRepo,
+		queueRepo:    queueRepo,
+		outboxRepo:   outboxRepo,
+		auditService: auditService,
 	}
 }
 
+func (s *ticketService) WithTenant(organizationID string) TicketService {
+	return &ticketService{
+		db:           s.db,
+		ticketRepo:   s.ticketRepo.WithTenant(organizationID),
+		eventRepo:    s.eventRepo.WithTenant(organizationID),
+		queueRepo:    s.queueRepo.WithTenant(organizationID),
+		outboxRepo:   s.outboxRepo.WithTenant(organizationID),
+		auditService: s.auditService.WithTenant(organizationID),
+		actor:        s.actor,
+	}
+}
+
+// WithActor mengembalikan salinan service yang mencatat perubahan atas nama actor
+func (s *ticketService) WithActor(actor Actor) TicketService {
+	clone := *s
+	clone.actor = actor
+	return &clone
+}
+
 func (s *ticketService) BuyTicket(ticket *entity.Ticket) (*entity.Ticket, error) {
+	// Request tanpa izin masuk ditolak sebelum ikut antre di lock baris event
+	if err := s.checkAdmission(ticket); err != nil {
+		return nil, err
+	}
+
 	err := s.db.Transaction(func(tx *gorm.DB) error {
 		ticketRepo := s.ticketRepo.WithTx(tx)
 		eventRepo := s.eventRepo.WithTx(tx)
@@ -56,6 +95,30 @@ func (s *ticketService) BuyTicket(ticket *entity.Ticket) (*entity.Ticket, error)
 			return errors.New("event is sold out")
 		}
 
+		// Dihitung di bawah lock baris event sehingga pembelian paralel dari user yang sama tetap antre
+		if event.MaxTicketsPerUser > 0 {
+			owned, err := ticketRepo.CountActiveByUserAndEvent(ticket.UserID.String(), event.ID.String())
+			if err != nil {
+				return err
+			}
+
+			if owned >= event.MaxTicketsPerUser {
+				return fmt.Errorf("ticket limit reached: at most %d tickets per user", event.MaxTicketsPerUser)
+			}
+		}
+
+		// Izin masuk baru dipakai setelah semua pengecekan lolos; rollback mengembalikannya
+		if event.QueueEnabled {
+			consumed, err := s.queueRepo.WithTx(tx).ConsumeAdmission(event.ID.String(), ticket.UserID.String(), utils.HashToken(ticket.QueueToken))
+			if err != nil {
+				return err
+			}
+
+			if !consumed {
+				return ErrQueueAdmissionRequired
+			}
+		}
+
 		ticket.BookingCode = generateBookingCode()
 		ticket.Status = entity.PurchasedTicket
 		ticket.PurchaseDate = time.Now()
@@ -64,19 +127,40 @@ func (s *ticketService) BuyTicket(ticket *entity.Ticket) (*entity.Ticket, error)
 		if err := ticketRepo.Create(ticket); err != nil {
 			return err
 		}
-		return nil
+
+		if err := eventRepo.AdjustTicketsSold(event.ID.String(), 1); err != nil {
+			return err
+		}
+
+		purchased := *ticket
+		purchased.Event = *event
+		return recordDomainEvent(s.outboxRepo.WithTx(tx), &domain.TicketPurchased{Ticket: purchased})
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	ticket, err = s.ticketRepo.FindByID(ticket.ID.String())
-	if err != nil {
-		return nil, err
+	s.auditService.Record(s.actor, "ticket.purchased", "ticket", ticket.ID.String(), nil, ticketAuditSnapshot(ticket))
+	return s.ticketRepo.FindByID(ticket.ID.String())
+}
+
+func (s *ticketService) checkAdmission(ticket *entity.Ticket) error {
+	queued, err := s.queueRepo.RequiresAdmission(ticket.EventID.String())
+	if err != nil || !queued {
+		return err
 	}
 
-	return ticket, nil
+	if ticket.QueueToken == "" {
+		return ErrQueueAdmissionRequired
+	}
+
+	entry, err := s.queueRepo.FindByHash(ticket.EventID.String(), utils.HashToken(ticket.QueueToken))
+	if err != nil || entry.UserID != ticket.UserID || !entry.IsAdmitted() {
+		return ErrQueueAdmissionRequired
+	}
+
+	return nil
 }
 
 func (s *ticketService) GetTicketByID(id string) (*entity.Ticket, error) {
@@ -96,8 +180,8 @@ func (s *ticketService) GetTicketsByEventID(eventID string, params utils.Paginat
 }
 
 func (s *ticketService) CancelTicket(id string) error {
-	// Ambil tiket
-	ticket, err := s.ticketRepo.FindByIDWithoutEvent(id)
+	// Ambil tiket beserta event untuk cek tanggal mulai dan isi email
+	ticket, err := s.ticketRepo.FindByID(id)
 	if err != nil {
 		return err
 	}
@@ -107,9 +191,112 @@ func (s *ticketService) CancelTicket(id string) error {
 		return errors.New("ticket cannot be cancelled")
 	}
 
-	// Update status
-	ticket.Status = entity.CancelledTicket
-	return s.ticketRepo.Update(ticket)
+	before := ticketAuditSnapshot(ticket)
+
+	// Update status dan counter event dalam satu transaksi
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		updated, err := s.ticketRepo.WithTx(tx).UpdateStatus(ticket.ID.String(), entity.PurchasedTicket, entity.CancelledTicket)
+		if err != nil {
+			return err
+		}
+
+		if !updated {
+			return errors.New("ticket cannot be cancelled")
+		}
+
+		if err := s.eventRepo.WithTx(tx).AdjustTicketsSold(ticket.EventID.String(), -1); err != nil {
+			return err
+		}
+
+		ticket.Status = entity.CancelledTicket
+		return recordDomainEvent(s.outboxRepo.WithTx(tx), &domain.TicketCancelled{Ticket: *ticket})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.auditService.Record(s.actor, "ticket.cancelled", "ticket", ticket.ID.String(), before, ticketAuditSnapshot(ticket))
+	return nil
+}
+
+// CheckIn mencatat kehadiran pemegang tiket di gate tertentu. Satu tiket hanya bisa check-in sekali.
+func (s *ticketService) CheckIn(bookingCode, gate string) (*entity.Ticket, error) {
+	ticket, err := s.ticketRepo.FindByBookingCode(bookingCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if ticket.Status != entity.PurchasedTicket {
+		return nil, errors.New("ticket is not valid for check-in")
+	}
+
+	if ticket.CheckedInAt != nil {
+		return nil, fmt.Errorf("ticket already checked in at %s", ticket.CheckedInAt.Format(time.RFC3339))
+	}
+
+	if ticket.Event.Status == entity.CompletedEvent {
+		return nil, errors.New("event has already ended")
+	}
+
+	before := ticketAuditSnapshot(ticket)
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		checkedIn, err := s.ticketRepo.WithTx(tx).CheckIn(ticket.ID.String(), gate, now)
+		if err != nil {
+			return err
+		}
+
+		if !checkedIn {
+			return errors.New("ticket already checked in")
+		}
+
+		ticket.CheckedInAt = &now
+		ticket.CheckInGate = gate
+		return recordDomainEvent(s.outboxRepo.WithTx(tx), &domain.TicketCheckedIn{Ticket: *ticket})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ticket, err = s.ticketRepo.FindByID(ticket.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(s.actor, "ticket.checked_in", "ticket", ticket.ID.String(), before, ticketAuditSnapshot(ticket))
+	return ticket, nil
+}
+
+// ExportEventTickets menulis daftar peserta event ke tabel CSV atau XLSX
+func (s *ticketService) ExportEventTickets(eventID string, w utils.TableWriter) error {
+	if _, err := s.eventRepo.FindByID(eventID); err != nil {
+		return err
+	}
+
+	err := w.WriteRow("ticket_id", "booking_code", "status", "price", "purchase_date", "cancelled_at",
+		"checked_in_at", "check_in_gate", "name", "email")
+	if err != nil {
+		return err
+	}
+
+	return s.ticketRepo.EachAttendeeByEventID(eventID, func(a repository.Attendee) error {
+		return w.WriteRow(a.TicketID, a.BookingCode, a.Status, a.Price, a.PurchaseDate, a.CancelledAt,
+			a.CheckedInAt, a.CheckInGate, a.UserName, a.UserEmail)
+	})
+}
+
+// ticketAuditSnapshot mengambil field tiket yang relevan untuk audit tanpa data event
+func ticketAuditSnapshot(ticket *entity.Ticket) map[string]interface{} {
+	return map[string]interface{}{
+		"event_id":      ticket.EventID,
+		"user_id":       ticket.UserID,
+		"status":        ticket.Status,
+		"price":         ticket.Price,
+		"booking_code":  ticket.BookingCode,
+		"checked_in_at": ticket.CheckedInAt,
+		"check_in_gate": ticket.CheckInGate,
+	}
 }
 
 // generateBookingCode menghasilkan kode booking unik
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"errors"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"time"
+)
+
+type UserService interface {
+	GetAllUsers(params utils.PaginationParams, keyword, role string) ([]entity.User, int64, error)
+	GetUserByID(id string) (*entity.User, error)
+	PromoteUser(id string) (*entity.User, error)
+	DemoteUser(id string) (*entity.User, error)
+	SuspendUser(id string) (*entity.User, error)
+	UnsuspendUser(id string) (*entity.User, error)
+	UnlockUser(id string) (*entity.User, error)
+	DeleteUser(id string) error
+	GetUserNotifications(id string, params utils.PaginationParams) ([]entity.Notification, int64, error)
+	WithTenant(organizationID string) UserService
+	WithActor(actor Actor) UserService
+}
+
+type userService struct {
+	userRepo       repository.UserRepository
+	authService    AuthService
+	accountService AccountService
+	loginGuard     LoginGuard
+	auditService   AuditService
+	actor          Actor
+}
+
+func NewUserService(userRepo repository.UserRepository, authService AuthService, accountService AccountService, loginGuard LoginGuard, auditService AuditService) UserService {
+	return &userService{
+		userRepo:       userRepo,
+		authService:    authService,
+		accountService: accountService,
+		loginGuard:     loginGuard,
+		auditService:   auditService,
+	}
+}
+
+func (s *userService) WithTenant(organizationID string) UserService {
+	return &userService{
+		userRepo:       s.userRepo.WithTenant(organizationID),
+		authService:    s.authService.WithTenant(organizationID),
+		accountService: s.accountService.WithTenant(organizationID),
+		loginGuard:     s.loginGuard.WithTenant(organizationID),
+		auditService:   s.auditService.WithTenant(organizationID),
+		actor:          s.actor,
+	}
+}
+
+// WithActor mengembalikan salinan service yang mencatat tindakan admin atas nama actor
+func (s *userService) WithActor(actor Actor) UserService {
+	clone := *s
+	clone.actor = actor
+	clone.authService = s.authService.WithActor(actor)
+	return &clone
+}
+
+func (s *userService) GetAllUsers(params utils.PaginationParams, keyword, role string) ([]entity.User, int64, error) {
+	return s.userRepo.FindAll(params, keyword, role)
+}
+
+func (s *userService) GetUserByID(id string) (*entity.User, error) {
+	return s.userRepo.FindByID(id)
+}
+
+func (s *userService) PromoteUser(id string) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == entity.AdminRole {
+		return nil, errors.New("user is already an admin")
+	}
+
+	if err := s.userRepo.UpdateRole(id, entity.AdminRole); err != nil {
+		return nil, err
+	}
+
+	return s.recordChange("user.promoted", user)
+}
+
+func (s *userService) DemoteUser(id string) (*entity.User, error) {
+	if s.actor.UserID == id {
+		return nil, errors.New("cannot demote yourself")
+	}
+
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role != entity.AdminRole {
+		return nil, errors.New("user is not an admin")
+	}
+
+	if err := ensureNotLastAdmin(s.userRepo, user); err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.UpdateRole(id, entity.UserRole); err != nil {
+		return nil, err
+	}
+
+	return s.recordChange("user.demoted", user)
+}
+
+// SuspendUser mencabut semua sesi user. API key miliknya tidak dicabut, tetapi ditolak
+// oleh middleware selama user di-suspend sehingga bisa dipakai lagi setelah unsuspend.
+func (s *userService) SuspendUser(id string) (*entity.User, error) {
+	if s.actor.UserID == id {
+		return nil, errors.New("cannot suspend yourself")
+	}
+
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsSuspended() {
+		return nil, errors.New("user is already suspended")
+	}
+
+	if err := ensureNotLastAdmin(s.userRepo, user); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.userRepo.UpdateSuspendedAt(id, &now); err != nil {
+		return nil, err
+	}
+
+	if err := s.authService.LogoutAll(id); err != nil {
+		return nil, err
+	}
+
+	return s.recordChange("user.suspended", user)
+}
+
+func (s *userService) UnsuspendUser(id string) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsSuspended() {
+		return nil, errors.New("user is not suspended")
+	}
+
+	if err := s.userRepo.UpdateSuspendedAt(id, nil); err != nil {
+		return nil, err
+	}
+
+	return s.recordChange("user.unsuspended", user)
+}
+
+func (s *userService) UnlockUser(id string) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loginGuard.Unlock(user.Email); err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(s.actor, accountUnlockedAction, "user", id, nil, nil)
+	return user, nil
+}
+
+func (s *userService) DeleteUser(id string) error {
+	if s.actor.UserID == id {
+		return errors.New("cannot delete yourself")
+	}
+
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureNotLastAdmin(s.userRepo, user); err != nil {
+		return err
+	}
+
+	if err := s.accountService.EraseUser(user); err != nil {
+		return err
+	}
+
+	s.auditService.Record(s.actor, "user.deleted", "user", id, userAuditSnapshot(user), nil)
+	return nil
+}
+
+// recordChange membaca ulang user setelah diubah lalu mencatat perbedaannya ke audit log
+func (s *userService) recordChange(action string, before *entity.User) (*entity.User, error) {
+	user, err := s.userRepo.FindByID(before.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(s.actor, action, "user", user.ID.String(), userAuditSnapshot(before), userAuditSnapshot(user))
+	return user, nil
+}
+
+// userAuditSnapshot mengambil field akses user untuk audit tanpa nama dan email,
+// sehingga audit log tidak perlu ikut dihapus saat user meminta datanya dihapus
+func userAuditSnapshot(user *entity.User) map[string]interface{} {
+	return map[string]interface{}{
+		"role":                  user.Role,
+		"suspended_at":          user.SuspendedAt,
+		"email_verified_at":     user.EmailVerifiedAt,
+		"two_factor_enabled_at": user.TwoFactorEnabledAt,
+	}
+}
+
+// ensureNotLastAdmin mencegah organisasi kehilangan admin terakhirnya
+func ensureNotLastAdmin(userRepo repository.UserRepository, user *entity.User) error {
+	if user.Role != entity.AdminRole || user.IsSuspended() {
+		return nil
+	}
+
+	count, err := userRepo.CountActiveByRole(entity.AdminRole)
+	if err != nil {
+		return err
+	}
+
+	if count <= 1 {
+		return errors.New("cannot remove the last admin")
+	}
+	return nil
+}
+
+func (s *userService) GetUserNotifications(id string, params utils.PaginationParams) ([]entity.Notification, int64, error) {
+	if _, err := s.userRepo.FindByID(id); err != nil {
+		return nil, 0, err
+	}
+	return s.accountService.GetNotifications(id, params)
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"event-ticketing/entity"
+	"testing"
+)
+
+func newUserServiceTest(t *testing.T) (*fakeUserRepository, *fakeAuditService, *entity.User, *entity.User) {
+	t.Helper()
+
+	userRepo := newFakeUserRepository()
+	admin := &entity.User{Name: "Admin", Email: "admin@example.com", Role: entity.AdminRole}
+	member := &entity.User{Name: "Member", Email: "member@example.com", Role: entity.UserRole}
+	for _, user := range []*entity.User{admin, member} {
+		if err := userRepo.Create(user); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return userRepo, &fakeAuditService{}, admin, member
+}
+
+func TestUserServiceRecordsAdminActions(t *testing.T) {
+	userRepo, auditService, admin, member := newUserServiceTest(t)
+	actor := Actor{UserID: admin.ID.String(), IP: "203.0.113.7", RequestID: "req-1"}
+	userService := NewUserService(userRepo, &fakeAuthService{}, nil, nil, auditService).WithActor(actor)
+
+	if _, err := userService.PromoteUser(member.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	record := auditService.last()
+	if record.action != "user.promoted" || record.entityID != member.ID.String() || record.actor != actor {
+		t.Fatalf("unexpected audit record: %+v", record)
+	}
+	if change := record.changes["role"]; change.Before != string(entity.UserRole) || change.After != string(entity.AdminRole) {
+		t.Fatalf("expected role change in audit record, got %+v", record.changes)
+	}
+
+	if _, err := userService.SuspendUser(member.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	record = auditService.last()
+	if record.action != "user.suspended" {
+		t.Fatalf("expected user.suspended, got %s", record.action)
+	}
+	if change, ok := record.changes["suspended_at"]; !ok || change.Before != nil || change.After == nil {
+		t.Fatalf("expected suspended_at change in audit record, got %+v", record.changes)
+	}
+
+	for _, record := range auditService.records {
+		for _, field := range []string{"name", "email", "pending_email"} {
+			if _, ok := record.changes[field]; ok {
+				t.Fatalf("audit record %s must not contain %s", record.action, field)
+			}
+		}
+	}
+}
+
+func TestUserServiceRejectsActionsOnActor(t *testing.T) {
+	userRepo, auditService, admin, _ := newUserServiceTest(t)
+	userService := NewUserService(userRepo, &fakeAuthService{}, nil, nil, auditService).
+		WithActor(Actor{UserID: admin.ID.String()})
+
+	if _, err := userService.DemoteUser(admin.ID.String()); err == nil || err.Error() != "cannot demote yourself" {
+		t.Fatalf("expected self demotion to be rejected, got %v", err)
+	}
+	if _, err := userService.SuspendUser(admin.ID.String()); err == nil || err.Error() != "cannot suspend yourself" {
+		t.Fatalf("expected self suspension to be rejected, got %v", err)
+	}
+	if len(auditService.records) != 0 {
+		t.Fatalf("expected rejected actions not to be audited, got %d records", len(auditService.records))
+	}
+}
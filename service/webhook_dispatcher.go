@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"event-ticketing/config"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// webhookBatchSize membatasi jumlah pengiriman yang diproses per putaran
+	webhookBatchSize = 100
+	// webhookResponseLimit membatasi potongan body respons partner yang disimpan
+	webhookResponseLimit = 1024
+	// webhookMaxRetryDelay membatasi jeda percobaan ulang pengiriman yang terus gagal
+	webhookMaxRetryDelay = 6 * time.Hour
+)
+
+// WebhookDispatcher mengirim pengiriman webhook yang sudah jatuh tempo. Body ditandatangani
+// dengan HMAC-SHA256 atas "<timestamp>.<body>" memakai secret subscription dan dikirim di
+// header X-Webhook-Signature. Pengiriman yang gagal dicoba lagi dengan jeda yang makin
+// panjang sampai WebhookMaxAttempts, lalu dipindah ke dead-letter.
+type WebhookDispatcher interface {
+	RunDue() error
+	Start()
+}
+
+type webhookDispatcher struct {
+	webhookRepo repository.WebhookRepository
+	client      *http.Client
+	config      config.Config
+}
+
+func NewWebhookDispatcher(webhookRepo repository.WebhookRepository, config config.Config) WebhookDispatcher {
+	return &webhookDispatcher{
+		webhookRepo: webhookRepo,
+		client: &http.Client{
+			Timeout:   config.WebhookTimeout,
+			Transport: newWebhookTransport(config.WebhookTimeout),
+			// Redirect tidak diikuti supaya payload hanya dikirim ke URL yang didaftarkan admin
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		config: config,
+	}
+}
+
+// RunDue mengirim semua pengiriman yang jatuh tempo lintas organisasi
+func (d *webhookDispatcher) RunDue() error {
+	now := time.Now()
+
+	deliveries, err := d.webhookRepo.FindDueDeliveries(now, webhookBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+
+		claimed, err := d.webhookRepo.ClaimDelivery(delivery.ID, *delivery.NextAttemptAt, now.Add(2*d.config.WebhookTimeout))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		d.attempt(delivery)
+		if err := d.webhookRepo.RecordDeliveryAttempt(delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attempt mengirim satu pengiriman dan menentukan status serta jadwal percobaan berikutnya
+func (d *webhookDispatcher) attempt(delivery *entity.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = 0
+	delivery.ResponseBody = ""
+	delivery.LastError = ""
+
+	err := d.send(delivery)
+	if err == nil {
+		delivery.Status = entity.DeliveredWebhookDelivery
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = err.Error()
+	// Subscription yang sudah dihapus tidak perlu dicoba ulang
+	if delivery.Subscription.ID.IsNil() || delivery.Attempts >= d.config.WebhookMaxAttempts {
+		utils.Log.Warnf("Webhook delivery %s moved to dead-letter after %d attempts: %v", delivery.ID, delivery.Attempts, err)
+		delivery.Status = entity.DeadWebhookDelivery
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	next := now.Add(d.retryDelay(delivery.Attempts))
+	delivery.Status = entity.PendingWebhookDelivery
+	delivery.NextAttemptAt = &next
+}
+
+func (d *webhookDispatcher) retryDelay(attempts int) time.Duration {
+	delay := d.config.WebhookRetryDelay
+	for i := 1; i < attempts && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > webhookMaxRetryDelay {
+		return webhookMaxRetryDelay
+	}
+	return delay
+}
+
+func (d *webhookDispatcher) send(delivery *entity.WebhookDelivery) error {
+	subscription := delivery.Subscription
+	if subscription.ID.IsNil() {
+		return errors.New("webhook subscription was deleted")
+	}
+
+	// Subscription lama mungkin terdaftar sebelum https diwajibkan
+	if _, err := parseWebhookURL(subscription.URL, webhookRequiresHTTPS(d.config)); err != nil {
+		return err
+	}
+
+	secret, err := utils.Decrypt(d.config.WebhookSecretKey, subscription.Secret)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt webhook secret: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", d.config.AppName+" Webhooks")
+	req.Header.Set("X-Webhook-ID", delivery.ID.String())
+	req.Header.Set("X-Webhook-Event", string(delivery.EventType))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhookPayload(secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	delivery.ResponseStatus = resp.StatusCode
+	delivery.ResponseBody = string(body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhookPayload menghasilkan signature hex yang bisa dihitung ulang partner untuk
+// memastikan payload berasal dari sistem ini dan belum diubah
+func SignWebhookPayload(secret []byte, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "." + payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Start menjalankan pengiriman webhook berkala di background
+func (d *webhookDispatcher) Start() {
+	if d.config.WebhookDispatchInterval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(d.config.WebhookDispatchInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := d.RunDue(); err != nil {
+				utils.Log.Errorf("Failed to dispatch webhooks: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"event-ticketing/config"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// webhookLookupTimeout membatasi resolve DNS host webhook saat subscription didaftarkan
+const webhookLookupTimeout = 5 * time.Second
+
+var errWebhookHostNotAllowed = errors.New("webhook url must point to a public host")
+
+// webhookBlockedNetworks berisi rentang non-publik yang tidak dicakup method net.IP
+// seperti IsPrivate dan IsLoopback
+var webhookBlockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",      // "this network"
+	"100.64.0.0/10",  // carrier-grade NAT
+	"192.0.0.0/24",   // IETF protocol assignments
+	"198.18.0.0/15",  // benchmarking
+	"240.0.0.0/4",    // reserved dan broadcast
+	"64:ff9b::/96",   // NAT64, bisa meneruskan ke alamat IPv4 internal
+	"64:ff9b:1::/48", // NAT64 lokal
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// isPublicWebhookIP menolak loopback, private, link-local, unspecified, multicast, dan
+// rentang khusus lain sehingga webhook tidak bisa dipakai untuk menjangkau jaringan internal
+func isPublicWebhookIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range webhookBlockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// webhookRequiresHTTPS menandakan webhook hanya boleh memakai https. Http hanya diizinkan di development.
+func webhookRequiresHTTPS(config config.Config) bool {
+	return config.Environment != "development"
+}
+
+// parseWebhookURL memastikan URL absolut dengan skema http atau https, atau hanya https
+// jika requireHTTPS.
+func parseWebhookURL(rawURL string, requireHTTPS bool) (*url.URL, error) {
+	endpoint, err := url.Parse(rawURL)
+	if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Hostname() == "" {
+		return nil, errors.New("webhook url must be an absolute http or https url")
+	}
+
+	if requireHTTPS && endpoint.Scheme != "https" {
+		return nil, errors.New("webhook url must use https")
+	}
+	return endpoint, nil
+}
+
+// validateWebhookURL memeriksa skema URL dan memastikan semua alamat host saat ini publik.
+// Alamat bisa berubah setelah didaftarkan, jadi dispatcher memeriksanya lagi saat connect.
+func validateWebhookURL(rawURL string, requireHTTPS bool) error {
+	endpoint, err := parseWebhookURL(rawURL, requireHTTPS)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookLookupTimeout)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, endpoint.Hostname())
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook host: %w", err)
+	}
+
+	for _, addr := range addrs {
+		if !isPublicWebhookIP(addr.IP) {
+			return errWebhookHostNotAllowed
+		}
+	}
+	return nil
+}
+
+// newWebhookTransport membuat transport yang hanya mau connect ke alamat publik. Alamat
+// diperiksa setelah DNS di-resolve, tepat sebelum connect, sehingga host yang diarahkan
+// ulang ke alamat internal setelah lolos validasi (DNS rebinding) tetap ditolak.
+// Proxy tidak dipakai karena koneksi ke proxy akan melewati pengecekan ini.
+func newWebhookTransport(timeout time.Duration) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			if ip := net.ParseIP(host); ip == nil || !isPublicWebhookIP(ip) {
+				return fmt.Errorf("%w: %s", errWebhookHostNotAllowed, host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Transport{
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   timeout,
+		ExpectContinueTimeout: time.Second,
+	}
+}
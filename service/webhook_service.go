@@ -0,0 +1,204 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"event-ticketing/config"
+	"event-ticketing/entity"
+	"event-ticketing/repository"
+	"event-ticketing/utils"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// CreatedWebhookSubscription berisi secret mentah yang hanya ditampilkan sekali saat dibuat
+type CreatedWebhookSubscription struct {
+	*entity.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// WebhookPayload adalah body JSON yang dikirim ke partner. ID adalah ID domain event,
+// sama untuk semua subscription dan tetap sama saat replay sehingga partner bisa membuang duplikat.
+type WebhookPayload struct {
+	ID        string                  `json:"id"`
+	Type      entity.WebhookEventType `json:"type"`
+	CreatedAt time.Time               `json:"created_at"`
+	Data      interface{}             `json:"data"`
+}
+
+type WebhookService interface {
+	CreateSubscription(subscription *entity.WebhookSubscription, secret string) (*CreatedWebhookSubscription, error)
+	GetAllSubscriptions(params utils.PaginationParams) ([]entity.WebhookSubscription, int64, error)
+	DeleteSubscription(id string) error
+	GetDeliveries(params utils.PaginationParams, filter repository.WebhookDeliveryFilter) ([]entity.WebhookDelivery, int64, error)
+	GetDelivery(id string) (*entity.WebhookDelivery, error)
+	ReplayDelivery(id string) (*entity.WebhookDelivery, error)
+	Dispatch(messageID uuid.UUID, occurredAt time.Time, eventType entity.WebhookEventType, data interface{}) error
+	WithTenant(organizationID string) WebhookService
+	WithActor(actor Actor) WebhookService
+}
+
+type webhookService struct {
+	webhookRepo  repository.WebhookRepository
+	auditService AuditService
+	config       config.Config
+	actor        Actor
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, auditService AuditService, config config.Config) WebhookService {
+	return &webhookService{
+		webhookRepo:  webhookRepo,
+		auditService: auditService,
+		config:       config,
+	}
+}
+
+func (s *webhookService) WithTenant(organizationID string) WebhookService {
+	return &webhookService{
+		webhookRepo:  s.webhookRepo.WithTenant(organizationID),
+		auditService: s.auditService.WithTenant(organizationID),
+		config:       s.config,
+		actor:        s.actor,
+	}
+}
+
+// WithActor mengembalikan salinan service yang mencatat perubahan atas nama actor
+func (s *webhookService) WithActor(actor Actor) WebhookService {
+	clone := *s
+	clone.actor = actor
+	return &clone
+}
+
+// CreateSubscription mendaftarkan URL partner. URL harus mengarah ke host publik dan, di luar
+// development, memakai https. Jika secret kosong, secret acak dibuat dan dikembalikan sekali ini saja.
+func (s *webhookService) CreateSubscription(subscription *entity.WebhookSubscription, secret string) (*CreatedWebhookSubscription, error) {
+	if err := validateWebhookURL(subscription.URL, webhookRequiresHTTPS(s.config)); err != nil {
+		return nil, err
+	}
+
+	eventTypes := subscription.EventTypeList()
+	if len(eventTypes) == 0 {
+		return nil, errors.New("at least one event type is required")
+	}
+	for _, eventType := range eventTypes {
+		if !isValidWebhookEventType(entity.WebhookEventType(eventType)) {
+			return nil, fmt.Errorf("unknown event type: %s", eventType)
+		}
+	}
+	subscription.EventTypes = strings.Join(eventTypes, ",")
+
+	if secret == "" {
+		random, err := utils.GenerateRandomToken(32)
+		if err != nil {
+			return nil, err
+		}
+		secret = entity.WebhookSecretPrefix + random
+	}
+
+	var err error
+	subscription.Secret, err = utils.Encrypt(s.config.WebhookSecretKey, []byte(secret))
+	if err != nil {
+		return nil, err
+	}
+	subscription.CreatedByID = uuid.FromStringOrNil(s.actor.UserID)
+
+	if err := s.webhookRepo.CreateSubscription(subscription); err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(s.actor, "webhook.created", "webhook", subscription.ID.String(), nil, subscription)
+	return &CreatedWebhookSubscription{WebhookSubscription: subscription, Secret: secret}, nil
+}
+
+func (s *webhookService) GetAllSubscriptions(params utils.PaginationParams) ([]entity.WebhookSubscription, int64, error) {
+	return s.webhookRepo.FindAllSubscriptions(params)
+}
+
+// DeleteSubscription menghentikan webhook. Pengiriman yang masih antre akan masuk dead-letter.
+func (s *webhookService) DeleteSubscription(id string) error {
+	subscription, err := s.webhookRepo.FindSubscriptionByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.webhookRepo.DeleteSubscription(id); err != nil {
+		return err
+	}
+
+	s.auditService.Record(s.actor, "webhook.deleted", "webhook", id, subscription, nil)
+	return nil
+}
+
+func (s *webhookService) GetDeliveries(params utils.PaginationParams, filter repository.WebhookDeliveryFilter) ([]entity.WebhookDelivery, int64, error) {
+	return s.webhookRepo.FindAllDeliveries(params, filter)
+}
+
+func (s *webhookService) GetDelivery(id string) (*entity.WebhookDelivery, error) {
+	return s.webhookRepo.FindDeliveryByID(id)
+}
+
+// ReplayDelivery mengirim ulang payload yang sama dari percobaan pertama, misalnya
+// setelah endpoint partner diperbaiki
+func (s *webhookService) ReplayDelivery(id string) (*entity.WebhookDelivery, error) {
+	delivery, err := s.webhookRepo.FindDeliveryByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.Status == entity.PendingWebhookDelivery {
+		return nil, errors.New("webhook delivery is still pending")
+	}
+
+	if _, err := s.webhookRepo.FindSubscriptionByID(delivery.SubscriptionID.String()); err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepo.ResetDelivery(id, time.Now()); err != nil {
+		return nil, err
+	}
+
+	s.auditService.Record(s.actor, "webhook_delivery.replayed", "webhook_delivery", id, nil, nil)
+	return s.webhookRepo.FindDeliveryByID(id)
+}
+
+// Dispatch mengantrekan pengiriman domain event messageID ke semua subscription yang
+// mendengarkan eventType. Aman dipanggil ulang untuk pesan yang sama.
+func (s *webhookService) Dispatch(messageID uuid.UUID, occurredAt time.Time, eventType entity.WebhookEventType, data interface{}) error {
+	subscriptions, err := s.webhookRepo.FindSubscriptionsByEventType(eventType)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(WebhookPayload{ID: messageID.String(), Type: eventType, CreatedAt: occurredAt, Data: data})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, subscription := range subscriptions {
+		delivery := &entity.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			MessageID:      messageID,
+			EventType:      eventType,
+			Payload:        string(payload),
+			Status:         entity.PendingWebhookDelivery,
+			NextAttemptAt:  &now,
+		}
+		if err := s.webhookRepo.CreateDelivery(delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isValidWebhookEventType(eventType entity.WebhookEventType) bool {
+	for _, t := range entity.WebhookEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"errors"
+	"event-ticketing/config"
+	"event-ticketing/entity"
+	"event-ticketing/utils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+func TestCreateSubscriptionRejectsNonPublicHosts(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		url         string
+		wantErr     error
+	}{
+		{"loopback", "development", "http://127.0.0.1:8080/hook", errWebhookHostNotAllowed},
+		{"loopback ipv6", "development", "http://[::1]/hook", errWebhookHostNotAllowed},
+		{"localhost", "development", "http://localhost/hook", errWebhookHostNotAllowed},
+		{"private", "production", "https://10.0.0.5/hook", errWebhookHostNotAllowed},
+		{"metadata link-local", "production", "https://169.254.169.254/latest/meta-data", errWebhookHostNotAllowed},
+		{"unspecified", "production", "https://0.0.0.0/hook", errWebhookHostNotAllowed},
+		{"carrier-grade nat", "production", "https://100.64.0.1/hook", errWebhookHostNotAllowed},
+		{"ipv4-mapped private", "production", "https://[::ffff:192.168.1.1]/hook", errWebhookHostNotAllowed},
+		{"http outside development", "production", "http://203.0.113.10/hook", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeWebhookRepository{}
+			svc := NewWebhookService(repo, &fakeAuditService{}, config.Config{Environment: tt.environment, WebhookSecretKey: "secret"})
+
+			_, err := svc.CreateSubscription(&entity.WebhookSubscription{URL: tt.url, EventTypes: string(entity.TicketPurchasedWebhook)}, "")
+			if err == nil {
+				t.Fatal("expected subscription to be rejected")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if len(repo.subscriptions) != 0 {
+				t.Fatal("rejected subscription was stored")
+			}
+		})
+	}
+}
+
+func TestCreateSubscriptionAcceptsPublicHTTPS(t *testing.T) {
+	repo := &fakeWebhookRepository{}
+	svc := NewWebhookService(repo, &fakeAuditService{}, config.Config{Environment: "production", WebhookSecretKey: "secret"})
+
+	_, err := svc.CreateSubscription(&entity.WebhookSubscription{URL: "https://203.0.113.10/hook", EventTypes: string(entity.TicketPurchasedWebhook)}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.subscriptions) != 1 {
+		t.Fatal("expected subscription to be stored")
+	}
+}
+
+// Host yang lolos validasi lalu di-resolve ke alamat internal tetap ditolak saat connect
+func TestWebhookDispatcherRefusesNonPublicAddress(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	cfg := config.Config{Environment: "development", WebhookSecretKey: "secret", WebhookTimeout: time.Second}
+	secret, err := utils.Encrypt(cfg.WebhookSecretKey, []byte("whsec_test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher := NewWebhookDispatcher(nil, cfg).(*webhookDispatcher)
+	err = dispatcher.send(&entity.WebhookDelivery{
+		BaseEntity:   entity.BaseEntity{ID: uuid.Must(uuid.NewV7())},
+		Payload:      "{}",
+		Subscription: entity.WebhookSubscription{BaseEntity: entity.BaseEntity{ID: uuid.Must(uuid.NewV7())}, URL: server.URL, Secret: secret},
+	})
+	if !errors.Is(err, errWebhookHostNotAllowed) {
+		t.Fatalf("expected %v, got %v", errWebhookHostNotAllowed, err)
+	}
+	if called {
+		t.Fatal("webhook was delivered to a loopback address")
+	}
+}
+
+func TestWebhookRetryDelayIsCapped(t *testing.T) {
+	dispatcher := NewWebhookDispatcher(nil, config.Config{WebhookRetryDelay: 30 * time.Second}).(*webhookDispatcher)
+
+	if got := dispatcher.retryDelay(1); got != 30*time.Second {
+		t.Fatalf("retryDelay(1) = %v, want 30s", got)
+	}
+	if got := dispatcher.retryDelay(3); got != 2*time.Minute {
+		t.Fatalf("retryDelay(3) = %v, want 2m", got)
+	}
+	for _, attempts := range []int{20, 64, 100} {
+		if got := dispatcher.retryDelay(attempts); got != webhookMaxRetryDelay {
+			t.Fatalf("retryDelay(%d) = %v, want %v", attempts, got, webhookMaxRetryDelay)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSearchLimit membatasi pencarian jadwal berikutnya agar ekspresi yang tidak
+// pernah cocok, misalnya 30 Februari, tidak membuat perulangan tanpa akhir
+const cronSearchLimit = 5 * 366 * 24 * time.Hour
+
+var cronShortcuts = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 1",
+	"@monthly": "0 0 1 * *",
+}
+
+// CronSchedule adalah jadwal cron lima field: menit, jam, tanggal, bulan, dan hari
+// (0 atau 7 untuk Minggu). Mendukung *, daftar (1,15), rentang (1-5), dan langkah (*/15).
+type CronSchedule struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	days        map[int]bool
+	months      map[int]bool
+	weekdays    map[int]bool
+	anyDay      bool
+	anyWeekday  bool
+	description string
+}
+
+func ParseCron(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if shortcut, ok := cronShortcuts[expr]; ok {
+		expr = shortcut
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, errors.New("schedule must have 5 fields: minute hour day month weekday")
+	}
+
+	schedule := &CronSchedule{description: expr}
+	var err error
+	if schedule.minutes, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("invalid minute field: %w", err)
+	}
+	if schedule.hours, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("invalid hour field: %w", err)
+	}
+	if schedule.days, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("invalid day field: %w", err)
+	}
+	if schedule.months, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("invalid month field: %w", err)
+	}
+	if schedule.weekdays, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("invalid weekday field: %w", err)
+	}
+
+	if schedule.weekdays[7] {
+		schedule.weekdays[0] = true
+	}
+	schedule.anyDay = fields[2] == "*"
+	schedule.anyWeekday = fields[4] == "*"
+
+	return schedule, nil
+}
+
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	values := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[i+1:])
+			if err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step %q", part)
+			}
+			part = part[:i]
+		}
+
+		start, end := min, max
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err1, err2 error
+			start, err1 = strconv.Atoi(bounds[0])
+			end, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			value, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			start = value
+			if step == 1 {
+				end = value
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return nil, fmt.Errorf("%q is out of range %d-%d", part, min, max)
+		}
+
+		for value := start; value <= end; value += step {
+			values[value] = true
+		}
+	}
+	return values, nil
+}
+
+// Next mengembalikan waktu terdekat setelah after yang cocok dengan jadwal menurut
+// zona waktu loc, atau waktu nol jika tidak ada yang cocok dalam lima tahun
+func (s *CronSchedule) Next(after time.Time, loc *time.Location) time.Time {
+	t := after.In(loc).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(cronSearchLimit)
+
+	for t.Before(limit) {
+		var next time.Time
+		switch {
+		case !s.months[int(t.Month())]:
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.matchesDay(t):
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !s.hours[t.Hour()]:
+			next = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case !s.minutes[t.Minute()]:
+			next = t.Add(time.Minute)
+		default:
+			return t
+		}
+
+		// Tengah malam yang hilang karena DST bisa dinormalisasi mundur oleh time.Date
+		if !next.After(t) {
+			next = t.Add(time.Hour)
+		}
+		t = next
+	}
+
+	return time.Time{}
+}
+
+// matchesDay mengikuti aturan cron: jika tanggal dan hari sama-sama dibatasi,
+// cukup salah satunya yang cocok
+func (s *CronSchedule) matchesDay(t time.Time) bool {
+	day := s.days[t.Day()]
+	weekday := s.weekdays[int(t.Weekday())]
+
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
+
+func (s *CronSchedule) String() string {
+	return s.description
+}
@@ -0,0 +1,463 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// qrVersion berisi ukuran blok untuk level koreksi M. Versi 1 sampai 10 cukup untuk
+// isi sampai 213 byte, jauh di atas panjang kode booking.
+type qrVersion struct {
+	ecPerBlock     int
+	blocks         []int
+	alignmentStart []int
+}
+
+var qrVersions = []qrVersion{
+	{10, []int{16}, nil},
+	{16, []int{28}, []int{6, 18}},
+	{26, []int{44}, []int{6, 22}},
+	{18, []int{32, 32}, []int{6, 26}},
+	{24, []int{43, 43}, []int{6, 30}},
+	{16, []int{27, 27, 27, 27}, []int{6, 34}},
+	{18, []int{31, 31, 31, 31}, []int{6, 22, 38}},
+	{22, []int{38, 38, 39, 39}, []int{6, 24, 42}},
+	{22, []int{36, 36, 36, 37, 37}, []int{6, 26, 46}},
+	{26, []int{43, 43, 43, 43, 44}, []int{6, 28, 50}},
+}
+
+// qrQuietZone adalah lebar margin kosong dalam modul yang diwajibkan spesifikasi
+const qrQuietZone = 4
+
+// QRCode adalah matriks modul QR. Nilai true berarti modul gelap.
+type QRCode struct {
+	Size    int
+	modules [][]bool
+	isFixed [][]bool
+}
+
+// EncodeQR membuat QR code mode byte dengan level koreksi M
+func EncodeQR(content string) (*QRCode, error) {
+	data := []byte(content)
+
+	for i, version := range qrVersions {
+		number := i + 1
+		capacity := 0
+		for _, size := range version.blocks {
+			capacity += size
+		}
+
+		countBits := 8
+		if number >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+len(data)*8 > capacity*8 {
+			continue
+		}
+
+		codewords := qrDataCodewords(data, countBits, capacity)
+		qr := newQRCode(number)
+		qr.drawFunctionPatterns(number, version)
+		qr.drawCodewords(qrInterleave(codewords, version))
+		qr.applyBestMask()
+		return qr, nil
+	}
+
+	return nil, errors.New("content too long for QR code")
+}
+
+// PNG menggambar QR code dengan scale piksel per modul beserta quiet zone
+func (q *QRCode) PNG(scale int) ([]byte, error) {
+	size := (q.Size + 2*qrQuietZone) * scale
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			if !q.modules[y][x] {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetGray((x+qrQuietZone)*scale+dx, (y+qrQuietZone)*scale+dy, color.Gray{Y: 0})
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func qrDataCodewords(data []byte, countBits, capacity int) []byte {
+	var bits []bool
+	appendBits := func(value, length int) {
+		for i := length - 1; i >= 0; i-- {
+			bits = append(bits, (value>>i)&1 == 1)
+		}
+	}
+
+	appendBits(0x4, 4)
+	appendBits(len(data), countBits)
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+
+	// Terminator sampai 4 bit nol lalu dibulatkan ke byte
+	for i := 0; i < 4 && len(bits) < capacity*8; i++ {
+		bits = append(bits, false)
+	}
+	for len(bits)%8 != 0 {
+		bits = append(bits, false)
+	}
+
+	codewords := make([]byte, 0, capacity)
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for j := 0; j < 8; j++ {
+			if bits[i+j] {
+				b |= 1 << (7 - j)
+			}
+		}
+		codewords = append(codewords, b)
+	}
+
+	for pad := byte(0xEC); len(codewords) < capacity; pad ^= 0xEC ^ 0x11 {
+		codewords = append(codewords, pad)
+	}
+	return codewords
+}
+
+// qrInterleave membagi data ke blok, menambah kode Reed-Solomon per blok, lalu
+// menyusun ulang kolom demi kolom sesuai urutan penempatan
+func qrInterleave(data []byte, version qrVersion) []byte {
+	divisor := qrReedSolomonDivisor(version.ecPerBlock)
+
+	var dataBlocks, ecBlocks [][]byte
+	offset := 0
+	maxLen := 0
+	for _, size := range version.blocks {
+		block := data[offset : offset+size]
+		offset += size
+		dataBlocks = append(dataBlocks, block)
+		ecBlocks = append(ecBlocks, qrReedSolomonRemainder(block, divisor))
+		if size > maxLen {
+			maxLen = size
+		}
+	}
+
+	var result []byte
+	for i := 0; i < maxLen; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < version.ecPerBlock; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+func qrReedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = qrMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = qrMultiply(root, 0x02)
+	}
+	return result
+}
+
+func qrReedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= qrMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+// qrMultiply mengalikan dua elemen GF(2^8) dengan polinomial 0x11D
+func qrMultiply(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+func newQRCode(version int) *QRCode {
+	size := version*4 + 17
+	qr := &QRCode{Size: size, modules: make([][]bool, size), isFixed: make([][]bool, size)}
+	for i := 0; i < size; i++ {
+		qr.modules[i] = make([]bool, size)
+		qr.isFixed[i] = make([]bool, size)
+	}
+	return qr
+}
+
+func (q *QRCode) setFixed(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.isFixed[y][x] = true
+}
+
+func (q *QRCode) drawFunctionPatterns(number int, version qrVersion) {
+	for i := 0; i < q.Size; i++ {
+		q.setFixed(6, i, i%2 == 0)
+		q.setFixed(i, 6, i%2 == 0)
+	}
+
+	q.drawFinder(3, 3)
+	q.drawFinder(q.Size-4, 3)
+	q.drawFinder(3, q.Size-4)
+
+	positions := version.alignmentStart
+	for i, x := range positions {
+		for j, y := range positions {
+			// Lewati posisi yang bertumpuk dengan finder pattern
+			if (i == 0 && j == 0) || (i == 0 && j == len(positions)-1) || (i == len(positions)-1 && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.setFixed(x+dx, y+dy, qrMax(qrAbs(dx), qrAbs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Format dipesan dulu, nilainya ditulis setelah mask dipilih
+	q.drawFormatBits(0)
+	q.drawVersionBits(number)
+}
+
+func (q *QRCode) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || y < 0 || x >= q.Size || y >= q.Size {
+				continue
+			}
+			distance := qrMax(qrAbs(dx), qrAbs(dy))
+			q.setFixed(x, y, distance != 2 && distance != 4)
+		}
+	}
+}
+
+// drawFormatBits menulis level koreksi M (00) dan nomor mask dengan kode BCH(15,5)
+func (q *QRCode) drawFormatBits(mask int) {
+	data := mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		q.setFixed(8, i, bit(i))
+	}
+	q.setFixed(8, 7, bit(6))
+	q.setFixed(8, 8, bit(7))
+	q.setFixed(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.setFixed(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		q.setFixed(q.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.setFixed(8, q.Size-15+i, bit(i))
+	}
+	q.setFixed(8, q.Size-8, true)
+}
+
+// drawVersionBits menulis nomor versi dengan kode BCH(18,6), hanya untuk versi 7 ke atas
+func (q *QRCode) drawVersionBits(number int) {
+	if number < 7 {
+		return
+	}
+
+	rem := number
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := number<<12 | rem
+
+	for i := 0; i < 18; i++ {
+		dark := (bits>>i)&1 == 1
+		a, b := q.Size-11+i%3, i/3
+		q.setFixed(a, b, dark)
+		q.setFixed(b, a, dark)
+	}
+}
+
+// drawCodewords mengisi modul data secara zig-zag dari kanan bawah, dua kolom sekaligus
+func (q *QRCode) drawCodewords(codewords []byte) {
+	i := 0
+	for right := q.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = q.Size - 1 - vert
+				}
+				if q.isFixed[y][x] || i >= len(codewords)*8 {
+					continue
+				}
+				q.modules[y][x] = (codewords[i>>3]>>(7-i&7))&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+func qrMaskApplies(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+func (q *QRCode) applyMask(mask int) {
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			if !q.isFixed[y][x] && qrMaskApplies(mask, x, y) {
+				q.modules[y][x] = !q.modules[y][x]
+			}
+		}
+	}
+}
+
+// applyBestMask mencoba kedelapan mask dan memakai yang skor penaltinya paling kecil
+func (q *QRCode) applyBestMask() {
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormatBits(mask)
+		if penalty := q.penalty(); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = mask, penalty
+		}
+		q.applyMask(mask)
+	}
+
+	q.applyMask(best)
+	q.drawFormatBits(best)
+}
+
+func (q *QRCode) penalty() int {
+	penalty := 0
+	dark := 0
+
+	line := func(get func(i int) bool) {
+		run := 1
+		for i := 1; i <= q.Size; i++ {
+			if i < q.Size && get(i) == get(i-1) {
+				run++
+				continue
+			}
+			if run >= 5 {
+				penalty += run - 2
+			}
+			run = 1
+		}
+
+		// Pola mirip finder 1:1:3:1:1 dengan empat modul terang di salah satu sisinya
+		for i := 0; i+11 <= q.Size; i++ {
+			matches := func(pattern string) bool {
+				for k := 0; k < 11; k++ {
+					if get(i+k) != (pattern[k] == '1') {
+						return false
+					}
+				}
+				return true
+			}
+			if matches("10111010000") || matches("00001011101") {
+				penalty += 40
+			}
+		}
+	}
+
+	for y := 0; y < q.Size; y++ {
+		line(func(i int) bool { return q.modules[y][i] })
+	}
+	for x := 0; x < q.Size; x++ {
+		line(func(i int) bool { return q.modules[i][x] })
+	}
+
+	for y := 0; y < q.Size; y++ {
+		for x := 0; x < q.Size; x++ {
+			if q.modules[y][x] {
+				dark++
+			}
+			if x+1 < q.Size && y+1 < q.Size {
+				color := q.modules[y][x]
+				if color == q.modules[y][x+1] && color == q.modules[y+1][x] && color == q.modules[y+1][x+1] {
+					penalty += 3
+				}
+			}
+		}
+	}
+
+	total := q.Size * q.Size
+	deviation := qrAbs(dark*20 - total*10)
+	penalty += ((deviation+total-1)/total - 1) * 10
+	return penalty
+}
+
+func qrAbs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func qrMax(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -91,6 +91,11 @@ func ConflictResponse(c *gin.Context, message string, err string) {
 	ErrorResponse(c, http.StatusConflict, message, err)
 }
 
+// 429 Too Many Requests
+func TooManyRequestsResponse(c *gin.Context, message string) {
+	ErrorResponse(c, http.StatusTooManyRequests, message, "")
+}
+
 // 500 Internal Server Error
 func InternalServerErrorResponse(c *gin.Context, message string, err string) {
 	ErrorResponse(c, http.StatusInternalServerError, message, err)
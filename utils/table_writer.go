@@ -0,0 +1,251 @@
+package utils
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	CSVFormat  = "csv"
+	XLSXFormat = "xlsx"
+	HTMLFormat = "html"
+)
+
+// TableWriter menulis data tabel baris demi baris sehingga data besar bisa langsung
+// dialirkan ke response tanpa ditampung di memori
+type TableWriter interface {
+	WriteRow(values ...interface{}) error
+	Close() error
+}
+
+// NewTableWriter membuat writer CSV, XLSX, atau HTML sesuai format
+func NewTableWriter(w io.Writer, format, sheetName string) (TableWriter, error) {
+	switch format {
+	case CSVFormat:
+		return &csvTableWriter{writer: csv.NewWriter(w)}, nil
+	case XLSXFormat:
+		return newXLSXTableWriter(w, sheetName)
+	case HTMLFormat:
+		return &htmlTableWriter{writer: bufio.NewWriter(w)}, nil
+	default:
+		return nil, errors.New("unsupported table format")
+	}
+}
+
+// TableContentType mengembalikan MIME type untuk format tabel
+func TableContentType(format string) string {
+	switch format {
+	case XLSXFormat:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case HTMLFormat:
+		return "text/html; charset=utf-8"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// formatCell mengubah nilai menjadi teks dan menandai apakah nilainya angka
+func formatCell(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case time.Time:
+		if v.IsZero() {
+			return "", false
+		}
+		return v.Format(time.RFC3339), false
+	case *time.Time:
+		if v == nil {
+			return "", false
+		}
+		return formatCell(*v)
+	default:
+		return fmt.Sprint(v), false
+	}
+}
+
+// escapeFormula memberi tanda kutip pada teks CSV yang diawali karakter formula
+// agar tidak dieksekusi saat dibuka di aplikasi spreadsheet
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+type csvTableWriter struct {
+	writer *csv.Writer
+	rows   int
+}
+
+func (t *csvTableWriter) WriteRow(values ...interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		text, numeric := formatCell(value)
+		if !numeric {
+			text = escapeFormula(text)
+		}
+		record[i] = text
+	}
+
+	if err := t.writer.Write(record); err != nil {
+		return err
+	}
+
+	// Flush berkala agar data mulai terkirim ke client
+	t.rows++
+	if t.rows%500 == 0 {
+		t.writer.Flush()
+	}
+	return t.writer.Error()
+}
+
+func (t *csvTableWriter) Close() error {
+	t.writer.Flush()
+	return t.writer.Error()
+}
+
+// xlsxTableWriter menulis workbook XLSX minimal dengan satu sheet. Sheet ditulis
+// terakhir di dalam arsip supaya barisnya bisa dialirkan langsung.
+type xlsxTableWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+}
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`
+
+const xlsxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`
+
+const xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+func newXLSXTableWriter(w io.Writer, sheetName string) (*xlsxTableWriter, error) {
+	archive := zip.NewWriter(w)
+
+	var name strings.Builder
+	if err := xml.EscapeText(&name, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, name.String())},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+	}
+
+	for _, part := range parts {
+		writer, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(writer, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	writer, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := bufio.NewWriter(writer)
+	_, err = sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+
+	return &xlsxTableWriter{archive: archive, sheet: sheet}, nil
+}
+
+func (t *xlsxTableWriter) WriteRow(values ...interface{}) error {
+	if _, err := t.sheet.WriteString("<row>"); err != nil {
+		return err
+	}
+
+	for _, value := range values {
+		text, numeric := formatCell(value)
+		if numeric {
+			fmt.Fprintf(t.sheet, "<c><v>%s</v></c>", text)
+			continue
+		}
+
+		t.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(t.sheet, []byte(text)); err != nil {
+			return err
+		}
+		t.sheet.WriteString("</t></is></c>")
+	}
+
+	_, err := t.sheet.WriteString("</row>")
+	return err
+}
+
+func (t *xlsxTableWriter) Close() error {
+	if _, err := t.sheet.WriteString("</sheetData></worksheet>"); err != nil {
+		return err
+	}
+	if err := t.sheet.Flush(); err != nil {
+		return err
+	}
+	return t.archive.Close()
+}
+
+// htmlTableWriter menulis tabel HTML sederhana yang bisa langsung disisipkan ke body
+// email. Baris pertama dianggap header.
+type htmlTableWriter struct {
+	writer *bufio.Writer
+	rows   int
+}
+
+func (t *htmlTableWriter) WriteRow(values ...interface{}) error {
+	cell := "td"
+	if t.rows == 0 {
+		cell = "th"
+		t.writer.WriteString(`<table border="1" cellpadding="4" cellspacing="0">`)
+	}
+	t.rows++
+
+	t.writer.WriteString("<tr>")
+	for _, value := range values {
+		text, numeric := formatCell(value)
+		if numeric {
+			fmt.Fprintf(t.writer, `<%s align="right">%s</%s>`, cell, text, cell)
+			continue
+		}
+		fmt.Fprintf(t.writer, "<%s>%s</%s>", cell, html.EscapeString(text), cell)
+	}
+	_, err := t.writer.WriteString("</tr>\n")
+	return err
+}
+
+func (t *htmlTableWriter) Close() error {
+	if t.rows > 0 {
+		t.writer.WriteString("</table>")
+	}
+	return t.writer.Flush()
+}